@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"log/slog"
 	"net/http"
 	"os"
@@ -9,11 +10,25 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/CAATHARSIS/movies-library/internal/auth"
 	"github.com/CAATHARSIS/movies-library/internal/config"
 	"github.com/CAATHARSIS/movies-library/internal/handlers"
 	"github.com/CAATHARSIS/movies-library/internal/logger"
 	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/repository/apikey"
+	"github.com/CAATHARSIS/movies-library/internal/repository/audit"
+	"github.com/CAATHARSIS/movies-library/internal/repository/collection"
+	"github.com/CAATHARSIS/movies-library/internal/repository/franchise"
+	"github.com/CAATHARSIS/movies-library/internal/repository/genre"
 	"github.com/CAATHARSIS/movies-library/internal/repository/movie"
+	"github.com/CAATHARSIS/movies-library/internal/repository/person"
+	"github.com/CAATHARSIS/movies-library/internal/repository/rating"
+	"github.com/CAATHARSIS/movies-library/internal/repository/relation"
+	"github.com/CAATHARSIS/movies-library/internal/repository/review"
+	"github.com/CAATHARSIS/movies-library/internal/repository/role"
+	"github.com/CAATHARSIS/movies-library/internal/repository/tag"
+	"github.com/CAATHARSIS/movies-library/internal/repository/user"
+	"github.com/CAATHARSIS/movies-library/internal/repository/watchlist"
 	"github.com/CAATHARSIS/movies-library/internal/service"
 	"github.com/CAATHARSIS/movies-library/pkg/database"
 
@@ -31,6 +46,20 @@ func main() {
 		log.Info("debug messages are enabled")
 	}
 
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		log.Error("Failed to load token keys", "error", err)
+		os.Exit(1)
+	}
+
+	if !verifier.Enabled() {
+		log.Warn("JWT_SECRET, JWT_PUBLIC_KEY_FILE and JWKS_FILE are not set, write routes are disabled")
+	}
+
+	if cfg.JWTSecret == "" {
+		log.Warn("JWT_SECRET is not set, users can't login")
+	}
+
 	migrationDB, err := database.NewPostgresDB(cfg)
 	if err != nil {
 		log.Error("Failed to connect to database", "error", err)
@@ -55,14 +84,82 @@ func main() {
 	}
 
 	movieRepo := movie.NewMoviePostgresRepo(appDB)
+	personRepo := person.NewPersonPostgresRepo(appDB)
+	genreRepo := genre.NewGenrePostgresRepo(appDB)
+	auditRepo := audit.NewAuditPostgresRepo(appDB)
+	roleRepo := role.NewRolePostgresRepo(appDB)
+	apiKeyRepo := apikey.NewAPIKeyPostgresRepo(appDB)
+	userRepo := user.NewUserPostgresRepo(appDB)
+	ratingRepo := rating.NewRatingPostgresRepo(appDB)
+	reviewRepo := review.NewReviewPostgresRepo(appDB)
+	watchlistRepo := watchlist.NewWatchlistPostgresRepo(appDB)
+	collectionRepo := collection.NewCollectionPostgresRepo(appDB)
+	relationRepo := relation.NewRelationPostgresRepo(appDB)
+	franchiseRepo := franchise.NewFranchisePostgresRepo(appDB)
+	tagRepo := tag.NewTagPostgresRepo(appDB)
+
+	cursorSecret := []byte(cfg.CursorSecret)
+	if len(cursorSecret) == 0 {
+		log.Warn("CURSOR_SECRET is not set, pagination cursors will not survive restart")
+		cursorSecret = make([]byte, 32)
+		rand.Read(cursorSecret)
+	}
 
-	movieService := service.NewMovieService(movieRepo)
-
-	movieHandler := handlers.NewMovieHandler(movieService, log)
+	movieService := service.NewMovieService(movieRepo, genreRepo, cursorSecret)
+
+	personService := service.NewPersonService(personRepo)
+	genreService := service.NewGenreService(genreRepo)
+	auditService := service.NewAuditService(auditRepo)
+	roleService := service.NewRoleService(roleRepo, cfg.AdminSubjects)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	userService := service.NewUserService(userRepo, auth.NewIssuer(cfg, service.AccessTokenTTL))
+	ratingService := service.NewRatingService(ratingRepo)
+	reviewService := service.NewReviewService(reviewRepo)
+	watchlistService := service.NewWatchlistService(watchlistRepo)
+	collectionService := service.NewCollectionService(collectionRepo)
+	relationService := service.NewRelationService(relationRepo, franchiseRepo)
+	franchiseService := service.NewFranchiseService(franchiseRepo)
+	tagService := service.NewTagService(tagRepo, movieRepo)
+
+	movieHandler := handlers.NewMovieHandler(movieService, log, cfg.Env)
+	personHandler := handlers.NewPersonHandler(personService, log, cfg.Env)
+	genreHandler := handlers.NewGenreHandler(genreService, log, cfg.Env)
+	auditHandler := handlers.NewAuditHandler(auditService, log, cfg.Env)
+	roleHandler := handlers.NewRoleHandler(roleService, log, cfg.Env)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService, log, cfg.Env)
+	userHandler := handlers.NewUserHandler(userService, log, cfg.Env)
+	ratingHandler := handlers.NewRatingHandler(ratingService, log, cfg.Env)
+	reviewHandler := handlers.NewReviewHandler(reviewService, log, cfg.Env)
+	watchlistHandler := handlers.NewWatchlistHandler(watchlistService, log, cfg.Env)
+	collectionHandler := handlers.NewCollectionHandler(collectionService, log, cfg.Env)
+	relationHandler := handlers.NewRelationHandler(relationService, log, cfg.Env)
+	franchiseHandler := handlers.NewFranchiseHandler(franchiseService, log, cfg.Env)
+	tagHandler := handlers.NewTagHandler(tagService, log, cfg.Env)
 
 	router := mux.NewRouter()
+	router.Use(middleware.NewRequestIDMiddleware())
 	router.Use(middleware.NewLoggingMiddleware(log))
+	router.Use(middleware.NewAuditMiddleware(auditRepo, log))
+	router.Use(middleware.NewAuthMiddleware(verifier, roleService, log))
+	router.Use(middleware.NewAPIKeyMiddleware(apiKeyService, log))
 	movieHandler.RegisterRoutes(router)
+	personHandler.RegisterRoutes(router)
+	genreHandler.RegisterRoutes(router)
+	auditHandler.RegisterRoutes(router)
+	roleHandler.RegisterRoutes(router)
+	apiKeyHandler.RegisterRoutes(router)
+	userHandler.RegisterRoutes(router)
+	ratingHandler.RegisterRoutes(router)
+	reviewHandler.RegisterRoutes(router)
+	watchlistHandler.RegisterRoutes(router)
+	collectionHandler.RegisterRoutes(router)
+	relationHandler.RegisterRoutes(router)
+	franchiseHandler.RegisterRoutes(router)
+	tagHandler.RegisterRoutes(router)
+
+	if len(cfg.AdminSubjects) == 0 {
+		log.Warn("ADMIN_SUBJECTS is not set, only admins stored in database can manage roles")
+	}
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
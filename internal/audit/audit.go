@@ -0,0 +1,78 @@
+// Package audit collects details of mutating API calls while they are handled,
+// layers below handlers add to the entry through request context
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+// Anonymous is the actor of requests made without credentials
+const Anonymous = "anonymous"
+
+// Sink stores audit entries
+type Sink interface {
+	Create(context.Context, *models.AuditEntry) error
+}
+
+type recorder struct {
+	mu    sync.Mutex
+	entry *models.AuditEntry
+}
+
+type recorderKey struct{}
+
+// NewContext returns context which collects details into entry
+func NewContext(ctx context.Context, entry *models.AuditEntry) context.Context {
+	return context.WithValue(ctx, recorderKey{}, &recorder{entry: entry})
+}
+
+// update changes entry of ctx, it does nothing when request is not audited
+func update(ctx context.Context, fn func(*models.AuditEntry)) {
+	rec, ok := ctx.Value(recorderKey{}).(*recorder)
+	if !ok {
+		return
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	fn(rec.entry)
+}
+
+// SetActor sets who makes the request
+func SetActor(ctx context.Context, actor string) {
+	update(ctx, func(e *models.AuditEntry) { e.Actor = actor })
+}
+
+// SetEntity sets entity which the request changes
+func SetEntity(ctx context.Context, entityType string, id any) {
+	update(ctx, func(e *models.AuditEntry) {
+		e.EntityType = entityType
+		e.EntityID = fmt.Sprint(id)
+	})
+}
+
+// SetBefore saves state of the entity before the change, v is encoded right away
+func SetBefore(ctx context.Context, v any) {
+	snapshot := encode(v)
+	update(ctx, func(e *models.AuditEntry) { e.Before = snapshot })
+}
+
+// SetAfter saves state of the entity after the change, v is encoded right away
+func SetAfter(ctx context.Context, v any) {
+	snapshot := encode(v)
+	update(ctx, func(e *models.AuditEntry) { e.After = snapshot })
+}
+
+func encode(v any) json.RawMessage {
+	snapshot, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	return snapshot
+}
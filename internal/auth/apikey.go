@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// apiKeyPrefix marks keys of this service, so leaked ones are easy to find by secret scanners
+const apiKeyPrefix = "mlk_"
+
+// apiKeyDisplayLength is length of the key beginning which is stored as is
+const apiKeyDisplayLength = len(apiKeyPrefix) + 8
+
+// KeyAuthenticator returns principal of API key
+type KeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// NewAPIKey generates random key and returns it with its display prefix
+func NewAPIKey() (key, prefix string) {
+	key = apiKeyPrefix + NewSecret()
+	return key, key[:apiKeyDisplayLength]
+}
+
+// NewSecret returns random URL-safe string with 256 bits of entropy
+func NewSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// HashSecret returns hash under which API key or refresh token is stored,
+// they are random so plain SHA-256 is enough
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
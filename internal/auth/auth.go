@@ -0,0 +1,35 @@
+// Package auth verifies credentials of API callers and keeps the authenticated principal in request context
+package auth
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Principal is the authenticated caller
+type Principal struct {
+	// Subject is "sub" claim of the token
+	Subject string
+	// Role is one of models.Role* values
+	Role string
+	// Scopes limit what API keys can do, they are nil for tokens which are limited only by role
+	Scopes []string
+	// UserID is id of local user, it's zero for API keys and tokens of other issuers
+	UserID int
+	// Claims holds all claims of the token
+	Claims jwt.MapClaims
+}
+
+type principalKey struct{}
+
+// NewContext returns context which carries principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns principal of ctx, ok is false for anonymous requests
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned when token is malformed, expired or signed by unknown key
+var ErrInvalidToken = errors.New("invalid token")
+
+type key struct {
+	id    string
+	alg   string
+	value any
+	// local is set for the secret Issuer signs tokens of local users with
+	local bool
+}
+
+// Verifier checks HS256 and RS256 tokens against configured keys
+type Verifier struct {
+	keys    []key
+	options []jwt.ParserOption
+}
+
+// NewVerifier loads keys from config: HS256 secret, PEM encoded RSA public key and JWKS file,
+// any of them may be omitted. Verifier without keys rejects every token
+func NewVerifier(cfg *config.Config) (*Verifier, error) {
+	v := &Verifier{
+		options: []jwt.ParserOption{
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+			jwt.WithExpirationRequired(),
+		},
+	}
+
+	if cfg.JWTIssuer != "" {
+		v.options = append(v.options, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		v.options = append(v.options, jwt.WithAudience(cfg.JWTAudience))
+	}
+
+	if cfg.JWTSecret != "" {
+		v.keys = append(v.keys, key{alg: jwt.SigningMethodHS256.Alg(), value: []byte(cfg.JWTSecret), local: true})
+	}
+
+	if cfg.JWTPublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key: %v", err)
+		}
+
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %v", err)
+		}
+
+		v.keys = append(v.keys, key{alg: jwt.SigningMethodRS256.Alg(), value: publicKey})
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		v.keys = append(v.keys, keys...)
+	}
+
+	return v, nil
+}
+
+// Enabled reports whether verifier has any key
+func (v *Verifier) Enabled() bool {
+	return len(v.keys) > 0
+}
+
+// Verify checks signature and claims of token and returns its principal
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+
+	parsed, err := jwt.ParseWithClaims(token, claims, v.keyFunc, v.options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidToken)
+	}
+
+	principal := &Principal{Subject: subject, Claims: claims}
+
+	// user id is trusted only in tokens signed by Issuer and only together with the matching subject,
+	// other issuers may use the same subjects for their own users
+	if k, err := v.pick(parsed); err == nil && k.local {
+		if uid, ok := claims["uid"].(float64); ok && subject == UserSubject(int(uid)) {
+			principal.UserID = int(uid)
+		}
+	}
+
+	return principal, nil
+}
+
+func (v *Verifier) keyFunc(token *jwt.Token) (any, error) {
+	k, err := v.pick(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.value, nil
+}
+
+// pick chooses key by algorithm of token and its "kid" header when both have one
+func (v *Verifier) pick(token *jwt.Token) (key, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	for _, k := range v.keys {
+		if k.alg != token.Method.Alg() {
+			continue
+		}
+
+		if kid != "" && k.id != "" && k.id != kid {
+			continue
+		}
+
+		return k, nil
+	}
+
+	return key{}, fmt.Errorf("no %s key for kid %q", token.Method.Alg(), kid)
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// loadJWKS reads RFC 7517 key set, keys which are not for signatures or of unsupported types are skipped
+func loadJWKS(path string) ([]key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %v", err)
+	}
+
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %v", err)
+	}
+
+	var keys []key
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		switch jwk.Kty {
+		case "RSA":
+			if jwk.Alg != "" && jwk.Alg != jwt.SigningMethodRS256.Alg() {
+				continue
+			}
+
+			n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+			if err != nil {
+				return nil, fmt.Errorf("key %q has invalid modulus: %v", jwk.Kid, err)
+			}
+
+			e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+			if err != nil {
+				return nil, fmt.Errorf("key %q has invalid exponent: %v", jwk.Kid, err)
+			}
+
+			publicKey := &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+
+			keys = append(keys, key{id: jwk.Kid, alg: jwt.SigningMethodRS256.Alg(), value: publicKey})
+
+		case "oct":
+			if jwk.Alg != "" && jwk.Alg != jwt.SigningMethodHS256.Alg() {
+				continue
+			}
+
+			secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+			if err != nil {
+				return nil, fmt.Errorf("key %q has invalid secret: %v", jwk.Kid, err)
+			}
+
+			keys = append(keys, key{id: jwk.Kid, alg: jwt.SigningMethodHS256.Alg(), value: secret})
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS %s has no usable keys", path)
+	}
+
+	return keys, nil
+}
+
+// userSubjectPrefix starts subjects of tokens issued to local users
+const userSubjectPrefix = "user:"
+
+// UserSubject returns subject of tokens issued to user with id
+func UserSubject(id int) string {
+	return userSubjectPrefix + strconv.Itoa(id)
+}
+
+// Issuer signs HS256 access tokens for local users with the secret verifier checks them with
+type Issuer struct {
+	secret   []byte
+	issuer   string
+	audience string
+	ttl      time.Duration
+}
+
+// NewIssuer creates issuer of tokens which live for ttl, it's disabled without JWT secret
+func NewIssuer(cfg *config.Config, ttl time.Duration) *Issuer {
+	return &Issuer{
+		secret:   []byte(cfg.JWTSecret),
+		issuer:   cfg.JWTIssuer,
+		audience: cfg.JWTAudience,
+		ttl:      ttl,
+	}
+}
+
+// Enabled reports whether issuer has a secret
+func (i *Issuer) Enabled() bool {
+	return len(i.secret) > 0
+}
+
+// TTL returns lifetime of issued tokens
+func (i *Issuer) TTL() time.Duration {
+	return i.ttl
+}
+
+// IssueUserToken returns access token of local user, its "uid" claim holds user id
+func (i *Issuer) IssueUserToken(userID int) (string, error) {
+	if !i.Enabled() {
+		return "", errors.New("tokens can't be issued without JWT secret")
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": UserSubject(userID),
+		"uid": userID,
+		"iat": now.Unix(),
+		"exp": now.Add(i.ttl).Unix(),
+	}
+
+	if i.issuer != "" {
+		claims["iss"] = i.issuer
+	}
+	if i.audience != "" {
+		claims["aud"] = i.audience
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+}
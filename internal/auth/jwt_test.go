@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writeJWKS(t *testing.T, kid string, key *rsa.PublicKey) string {
+	t.Helper()
+
+	set := map[string]any{
+		"keys": []map[string]string{
+			{"kty": "oct", "use": "enc", "k": "c2VjcmV0"},
+			{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			},
+		},
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func TestVerifier(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewVerifier(&config.Config{
+		JWTSecret:   "secret",
+		JWKSFile:    writeJWKS(t, "key-1", &privateKey.PublicKey),
+		JWTIssuer:   "https://issuer.example",
+		JWTAudience: "movies-library",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "alice",
+			"iss": "https://issuer.example",
+			"aud": "movies-library",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	with := func(key string, value any) jwt.MapClaims {
+		claims := valid()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"HS256", sign(t, jwt.SigningMethodHS256, []byte("secret"), "", valid()), true},
+		{"RS256 from JWKS", sign(t, jwt.SigningMethodRS256, privateKey, "key-1", valid()), true},
+		{"RS256 without kid", sign(t, jwt.SigningMethodRS256, privateKey, "", valid()), true},
+		{"unknown kid", sign(t, jwt.SigningMethodRS256, privateKey, "key-2", valid()), false},
+		{"wrong secret", sign(t, jwt.SigningMethodHS256, []byte("guess"), "", valid()), false},
+		{"unsupported algorithm", sign(t, jwt.SigningMethodHS512, []byte("secret"), "", valid()), false},
+		{"expired", sign(t, jwt.SigningMethodHS256, []byte("secret"), "", with("exp", time.Now().Add(-time.Minute).Unix())), false},
+		{"no expiration", sign(t, jwt.SigningMethodHS256, []byte("secret"), "", with("exp", nil)), false},
+		{"no subject", sign(t, jwt.SigningMethodHS256, []byte("secret"), "", with("sub", nil)), false},
+		{"wrong issuer", sign(t, jwt.SigningMethodHS256, []byte("secret"), "", with("iss", "https://evil.example")), false},
+		{"wrong audience", sign(t, jwt.SigningMethodHS256, []byte("secret"), "", with("aud", "other")), false},
+		{"garbage", "not.a.token", false},
+	}
+
+	for _, tt := range tests {
+		principal, err := verifier.Verify(tt.token)
+
+		if !tt.valid {
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("%s: expected ErrInvalidToken, got %v", tt.name, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		if principal.Subject != "alice" {
+			t.Errorf("%s: expected subject alice, got %q", tt.name, principal.Subject)
+		}
+	}
+}
+
+func TestVerifier_NoKeys(t *testing.T) {
+	verifier, err := NewVerifier(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifier.Enabled() {
+		t.Error("Expected verifier without keys to be disabled")
+	}
+
+	token := sign(t, jwt.SigningMethodHS256, []byte(""), "", jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+	if _, err := verifier.Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestIssuer_UserToken(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "secret", JWTIssuer: "movies-library"}
+
+	verifier, err := NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := NewIssuer(cfg, time.Minute).IssueUserToken(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err := verifier.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if principal.Subject != "user:42" || principal.UserID != 42 {
+		t.Errorf("Expected user 42, got subject %q and user id %d", principal.Subject, principal.UserID)
+	}
+
+	// uid claim of a token for another subject is not trusted
+	forged := sign(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{
+		"sub": "alice",
+		"uid": 42,
+		"iss": "movies-library",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+
+	principal, err = verifier.Verify(forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if principal.UserID != 0 {
+		t.Errorf("Expected uid of other subject to be ignored, got %d", principal.UserID)
+	}
+
+	if _, err := NewIssuer(&config.Config{}, time.Minute).IssueUserToken(42); err == nil {
+		t.Error("Expected issuer without secret to fail")
+	}
+}
+
+func TestVerifier_ExternalUserID(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewVerifier(&config.Config{
+		JWTSecret: "secret",
+		JWKSFile:  writeJWKS(t, "key-1", &privateKey.PublicKey),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// external issuer can't act as local user even with subject and uid of one
+	token := sign(t, jwt.SigningMethodRS256, privateKey, "key-1", jwt.MapClaims{
+		"sub": "user:1",
+		"uid": 1,
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+
+	principal, err := verifier.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if principal.Subject != "user:1" || principal.UserID != 0 {
+		t.Errorf("Expected external token to have no user id, got subject %q and user id %d", principal.Subject, principal.UserID)
+	}
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+// Action is an operation which needs permission, reads of catalog are public and need none
+type Action string
+
+// Actions checked by services
+const (
+	// ActionEdit creates and updates movies, people, genres, collections, franchises and relations of movies
+	ActionEdit Action = "edit"
+	// ActionDelete deletes movies, people, credits, genres, collections and franchises
+	ActionDelete Action = "delete"
+	// ActionPurge removes movies from trash for good
+	ActionPurge Action = "purge"
+	// ActionReadTrash lists movies in trash, which are hidden from public reads
+	ActionReadTrash Action = "read trash"
+	// ActionManageUsers reads and changes roles of users
+	ActionManageUsers Action = "manage users"
+	// ActionReadAudit reads audit log
+	ActionReadAudit Action = "read audit log"
+	// ActionManageKeys creates, lists and revokes API keys
+	ActionManageKeys Action = "manage api keys"
+	// ActionModerate approves and rejects reviews of users
+	ActionModerate Action = "moderate reviews"
+)
+
+// policy maps actions to the least role allowed to do them
+var policy = map[Action]string{
+	ActionEdit:        models.RoleEditor,
+	ActionDelete:      models.RoleAdmin,
+	ActionPurge:       models.RoleAdmin,
+	ActionReadTrash:   models.RoleEditor,
+	ActionManageUsers: models.RoleAdmin,
+	ActionReadAudit:   models.RoleAdmin,
+	ActionManageKeys:  models.RoleAdmin,
+	ActionModerate:    models.RoleEditor,
+}
+
+// scopes maps actions to scope which API keys need for them
+var scopes = map[Action]string{
+	ActionEdit:        models.ScopeMoviesWrite,
+	ActionDelete:      models.ScopeMoviesDelete,
+	ActionPurge:       models.ScopeMoviesDelete,
+	ActionReadTrash:   models.ScopeMoviesRead,
+	ActionManageUsers: models.ScopeUsersManage,
+	ActionReadAudit:   models.ScopeAuditRead,
+	ActionManageKeys:  models.ScopeKeysManage,
+	ActionModerate:    models.ScopeReviewsModerate,
+}
+
+var roleRanks = map[string]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleAdmin:  3,
+}
+
+// ValidRole reports whether role is one of known roles
+func ValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// ValidScope reports whether scope allows any action
+func ValidScope(scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ScopesRole returns the least role which is enough for every action allowed by scopes,
+// API keys act with it while scopes keep them from other actions of the role
+func ScopesRole(keyScopes []string) string {
+	role := models.RoleViewer
+	for action, scope := range scopes {
+		if slices.Contains(keyScopes, scope) && roleRanks[policy[action]] > roleRanks[role] {
+			role = policy[action]
+		}
+	}
+
+	return role
+}
+
+// RoleResolver returns role of authenticated subject
+type RoleResolver interface {
+	ResolveRole(ctx context.Context, subject string) (string, error)
+}
+
+// Authorize checks that principal of ctx may do action, it returns models.ErrUnauthorized
+// for anonymous callers and models.ErrForbidden when role or scopes are not enough
+func Authorize(ctx context.Context, action Action) error {
+	required, ok := policy[action]
+	if !ok {
+		return fmt.Errorf("%w: unknown action %q", models.ErrForbidden, action)
+	}
+
+	p, ok := FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("%w: %s needs %s role", models.ErrUnauthorized, action, required)
+	}
+
+	if roleRanks[p.Role] < roleRanks[required] {
+		return fmt.Errorf("%w: %s needs %s role, %s has %q", models.ErrForbidden, action, required, p.Subject, p.Role)
+	}
+
+	if p.Scopes == nil {
+		return nil
+	}
+
+	scope, ok := scopes[action]
+	if !ok {
+		return fmt.Errorf("%w: %s can't be done with api key", models.ErrForbidden, action)
+	}
+
+	if !slices.Contains(p.Scopes, scope) {
+		return fmt.Errorf("%w: %s needs %s scope", models.ErrForbidden, action, scope)
+	}
+
+	return nil
+}
+
+// CurrentUser returns id of local user who makes the request, it returns models.ErrUnauthorized
+// for anonymous callers, API keys and tokens of other issuers
+func CurrentUser(ctx context.Context) (int, error) {
+	p, ok := FromContext(ctx)
+	if !ok || p.UserID == 0 {
+		return 0, fmt.Errorf("%w: user access token is required", models.ErrUnauthorized)
+	}
+
+	return p.UserID, nil
+}
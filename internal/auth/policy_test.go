@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		role     string
+		scopes   []string
+		action   Action
+		expected error
+	}{
+		{"", nil, ActionEdit, models.ErrUnauthorized},
+		{models.RoleViewer, nil, ActionEdit, models.ErrForbidden},
+		{models.RoleEditor, nil, ActionEdit, nil},
+		{models.RoleEditor, nil, ActionDelete, models.ErrForbidden},
+		{models.RoleEditor, nil, ActionPurge, models.ErrForbidden},
+		{models.RoleEditor, nil, ActionManageUsers, models.ErrForbidden},
+		{models.RoleEditor, nil, ActionModerate, nil},
+		{models.RoleViewer, nil, ActionModerate, models.ErrForbidden},
+		{"", nil, ActionReadTrash, models.ErrUnauthorized},
+		{models.RoleViewer, nil, ActionReadTrash, models.ErrForbidden},
+		{models.RoleEditor, nil, ActionReadTrash, nil},
+		{models.RoleAdmin, nil, ActionEdit, nil},
+		{models.RoleAdmin, nil, ActionDelete, nil},
+		{models.RoleAdmin, nil, ActionPurge, nil},
+		{models.RoleAdmin, nil, ActionManageUsers, nil},
+		{models.RoleAdmin, nil, ActionReadAudit, nil},
+		{"superuser", nil, ActionEdit, models.ErrForbidden},
+		{models.RoleAdmin, nil, Action("unknown"), models.ErrForbidden},
+		{models.RoleEditor, []string{models.ScopeMoviesWrite}, ActionEdit, nil},
+		{models.RoleViewer, []string{models.ScopeMoviesRead}, ActionEdit, models.ErrForbidden},
+		{models.RoleEditor, []string{models.ScopeMoviesRead}, ActionEdit, models.ErrForbidden},
+		{models.RoleAdmin, []string{models.ScopeMoviesWrite}, ActionDelete, models.ErrForbidden},
+		{models.RoleEditor, []string{models.ScopeMoviesWrite}, ActionModerate, models.ErrForbidden},
+		{models.RoleAdmin, []string{models.ScopeMoviesDelete}, ActionPurge, nil},
+		{models.RoleEditor, []string{models.ScopeMoviesRead}, ActionReadTrash, nil},
+		{models.RoleEditor, []string{models.ScopeReviewsModerate}, ActionModerate, nil},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.role != "" {
+			ctx = NewContext(ctx, &Principal{Subject: "alice", Role: tt.role, Scopes: tt.scopes})
+		}
+
+		err := Authorize(ctx, tt.action)
+		if tt.expected == nil && err != nil {
+			t.Errorf("Role %q, scopes %v, action %q: unexpected error %v", tt.role, tt.scopes, tt.action, err)
+		}
+		if tt.expected != nil && !errors.Is(err, tt.expected) {
+			t.Errorf("Role %q, scopes %v, action %q: expected %v, got %v", tt.role, tt.scopes, tt.action, tt.expected, err)
+		}
+	}
+}
+
+func TestScopesRole(t *testing.T) {
+	tests := []struct {
+		scopes   []string
+		expected string
+	}{
+		{nil, models.RoleViewer},
+		{[]string{models.ScopeMoviesRead}, models.RoleEditor},
+		{[]string{models.ScopeMoviesWrite}, models.RoleEditor},
+		{[]string{models.ScopeMoviesWrite, models.ScopeMoviesDelete}, models.RoleAdmin},
+		{[]string{"unknown"}, models.RoleViewer},
+	}
+
+	for _, tt := range tests {
+		if role := ScopesRole(tt.scopes); role != tt.expected {
+			t.Errorf("ScopesRole(%v) = %q, expected %q", tt.scopes, role, tt.expected)
+		}
+	}
+
+	for _, scope := range scopes {
+		if !ValidScope(scope) {
+			t.Errorf("Expected %q to be valid scope", scope)
+		}
+	}
+
+	if ValidScope("movies:admin") {
+		t.Error("Expected unknown scope to be invalid")
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strings"
+)
 
 // Config holds settings of application
 type Config struct {
@@ -11,17 +14,38 @@ type Config struct {
 	DBName     string
 	ServerPort string
 	Env        string
+	// signs pagination cursors, random secret is generated when it's empty
+	CursorSecret string
+	// subjects of tokens which are always admins, comma separated
+	AdminSubjects []string
+	// verifies HS256 tokens
+	JWTSecret string
+	// PEM file with RSA public key verifying RS256 tokens
+	JWTPublicKeyFile string
+	// JWKS file with keys verifying tokens, key is picked by "kid" header
+	JWKSFile string
+	// expected "iss" and "aud" claims, they are not checked when empty
+	JWTIssuer   string
+	JWTAudience string
 }
 
 func Load() *Config {
 	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "postgres"),
-		DBName:     getEnv("DB_NAME", "movie_library"),
-		ServerPort: getEnv("SERVER_PORT", "8080"),
-		Env:        getEnv("ENV", "local"),
+		DBHost:       getEnv("DB_HOST", "localhost"),
+		DBPort:       getEnv("DB_PORT", "5432"),
+		DBUser:       getEnv("DB_USER", "postgres"),
+		DBPassword:   getEnv("DB_PASSWORD", "postgres"),
+		DBName:       getEnv("DB_NAME", "movie_library"),
+		ServerPort:   getEnv("SERVER_PORT", "8080"),
+		Env:          getEnv("ENV", "local"),
+		CursorSecret: getEnv("CURSOR_SECRET", ""),
+
+		AdminSubjects:    getList("ADMIN_SUBJECTS"),
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTPublicKeyFile: getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWKSFile:         getEnv("JWKS_FILE", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", ""),
+		JWTAudience:      getEnv("JWT_AUDIENCE", ""),
 	}
 }
 
@@ -32,3 +56,14 @@ func getEnv(key, defaultValue string) string {
 
 	return defaultValue
 }
+
+func getList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewAPIKeyHandler(service service.APIKeyService, log *slog.Logger, env string) *APIKeyHandler {
+	return &APIKeyHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers API key management routes, service lets through only admins
+func (h *APIKeyHandler) RegisterRoutes(router *mux.Router) {
+	admin := router.NewRoute().Subrouter()
+	admin.Use(middleware.RequireAuth)
+	admin.HandleFunc("/admin/api-keys", h.CreateAPIKey).Methods("POST")
+	admin.HandleFunc("/admin/api-keys", h.ListAPIKeys).Methods("GET")
+	admin.HandleFunc("/admin/api-keys/{id}", h.RevokeAPIKey).Methods("DELETE")
+}
+
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var key models.APIKey
+
+	if err := decodeJSON(r, &key); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode api key body", "error", err)
+		return
+	}
+
+	if err := h.service.CreateAPIKey(r.Context(), &key); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to create api key", "error", err)
+		return
+	}
+
+	h.log.Info("API key created succesfully", "ID", key.ID, "prefix", key.Prefix)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.ListAPIKeys(r.Context())
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list api keys", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid api key id", models.ErrBadRequest))
+		h.log.Error("Invalid api key id", "error", err)
+		return
+	}
+
+	if err := h.service.RevokeAPIKey(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to revoke api key", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("API key was revoked succesfully", "ID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type memoryAPIKeyRepo struct {
+	keys []*models.APIKey
+}
+
+func (r *memoryAPIKeyRepo) Create(ctx context.Context, key *models.APIKey) error {
+	key.ID = len(r.keys) + 1
+	key.CreatedAt = time.Now()
+	stored := *key
+	stored.Key = ""
+	r.keys = append(r.keys, &stored)
+	return nil
+}
+
+func (r *memoryAPIKeyRepo) List(ctx context.Context) ([]*models.APIKey, error) {
+	return r.keys, nil
+}
+
+func (r *memoryAPIKeyRepo) Revoke(ctx context.Context, id int) error {
+	for _, key := range r.keys {
+		if key.ID == id && key.RevokedAt == nil {
+			now := time.Now()
+			key.RevokedAt = &now
+			return nil
+		}
+	}
+
+	return models.ErrNotFound
+}
+
+func (r *memoryAPIKeyRepo) Use(ctx context.Context, hash string) (*models.APIKey, error) {
+	for _, key := range r.keys {
+		if key.Hash == hash && key.RevokedAt == nil && (key.ExpiresAt == nil || key.ExpiresAt.After(time.Now())) {
+			now := time.Now()
+			key.LastUsedAt = &now
+			return key, nil
+		}
+	}
+
+	return nil, models.ErrNotFound
+}
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	apiKeyService := service.NewAPIKeyService(&memoryAPIKeyRepo{})
+	handler := NewAPIKeyHandler(apiKeyService, logger.NewLogger("local"), "local")
+
+	expired := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		role   string
+		body   string
+		status int
+	}{
+		{models.RoleEditor, `{"name": "ingest", "scopes": ["movies:write"]}`, http.StatusForbidden},
+		{models.RoleAdmin, `{"name": "ingest", "scopes": ["movies:admin"]}`, http.StatusUnprocessableEntity},
+		{models.RoleAdmin, `{"name": "ingest", "scopes": []}`, http.StatusUnprocessableEntity},
+		{models.RoleAdmin, `{"name": "ingest", "scopes": ["movies:read"], "expires_at": "` + expired + `"}`, http.StatusUnprocessableEntity},
+		{models.RoleAdmin, `{"name": "ingest", "scopes": ["movies:read", "movies:write"]}`, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		router := mux.NewRouter()
+		router.Use(authenticatedAs(tt.role))
+		handler.RegisterRoutes(router)
+
+		req := httptest.NewRequest("POST", "/admin/api-keys", bytes.NewReader([]byte(tt.body)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("Role %s creating %s: expected status %d, got %d", tt.role, tt.body, tt.status, w.Code)
+			continue
+		}
+
+		if w.Code != http.StatusCreated {
+			continue
+		}
+
+		var key models.APIKey
+		if err := json.Unmarshal(w.Body.Bytes(), &key); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(key.Key, key.Prefix) || key.CreatedBy != "tester" {
+			t.Errorf("Expected key with its prefix created by tester, got %+v", key)
+		}
+	}
+}
+
+func TestAPIKeyHandler_Authenticate(t *testing.T) {
+	repo := &memoryAPIKeyRepo{}
+	apiKeyService := service.NewAPIKeyService(repo)
+	logger := logger.NewLogger("local")
+	handler := NewAPIKeyHandler(apiKeyService, logger, "local")
+
+	admin := mux.NewRouter()
+	admin.Use(authenticatedAs(models.RoleAdmin))
+	handler.RegisterRoutes(admin)
+
+	create := func(scopes string) string {
+		req := httptest.NewRequest("POST", "/admin/api-keys", bytes.NewReader([]byte(`{"name": "ingest", "scopes": `+scopes+`}`)))
+		w := httptest.NewRecorder()
+		admin.ServeHTTP(w, req)
+
+		var key models.APIKey
+		if err := json.Unmarshal(w.Body.Bytes(), &key); err != nil {
+			t.Fatal(err)
+		}
+		return key.Key
+	}
+
+	writeKey := create(`["movies:write"]`)
+	readKey := create(`["movies:read"]`)
+
+	mockService := NewMockMovieService().(*MockMovieService)
+	mockService.AddTestMovies(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAPIKeyMiddleware(apiKeyService, logger))
+	NewMovieHandler(mockService, logger, "local").RegisterRoutes(router)
+	handler.RegisterRoutes(router)
+
+	patch := func(header, value string) int {
+		req := httptest.NewRequest("PATCH", "/movies/1", bytes.NewReader([]byte(`{"title": "Heat (1995)"}`)))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if status := patch("X-API-Key", writeKey); status != http.StatusOK {
+		t.Errorf("Expected key in X-API-Key to be accepted, got %d", status)
+	}
+
+	if status := patch("Authorization", "ApiKey "+writeKey); status != http.StatusOK {
+		t.Errorf("Expected key in Authorization to be accepted, got %d", status)
+	}
+
+	if status := patch("X-API-Key", "mlk_guess"); status != http.StatusUnauthorized {
+		t.Errorf("Expected unknown key to be rejected, got %d", status)
+	}
+
+	req := httptest.NewRequest("GET", "/admin/api-keys", nil)
+	req.Header.Set("X-API-Key", readKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected api key to be forbidden from managing keys, got %d", w.Code)
+	}
+
+	if repo.keys[0].LastUsedAt == nil {
+		t.Error("Expected last used time to be set")
+	}
+
+	w = httptest.NewRecorder()
+	admin.ServeHTTP(w, httptest.NewRequest("DELETE", "/admin/api-keys/1", nil))
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+
+	if status := patch("X-API-Key", writeKey); status != http.StatusUnauthorized {
+		t.Errorf("Expected revoked key to be rejected, got %d", status)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type AuditHandler struct {
+	service service.AuditService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewAuditHandler(service service.AuditService, log *slog.Logger, env string) *AuditHandler {
+	return &AuditHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers audit log routes, service lets through only admins
+func (h *AuditHandler) RegisterRoutes(router *mux.Router) {
+	admin := router.NewRoute().Subrouter()
+	admin.Use(middleware.RequireAuth)
+	admin.HandleFunc("/audit", h.ListAudit).Methods("GET")
+}
+
+func (h *AuditHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := &models.AuditQuery{
+		Actor:      params.Get("actor"),
+		Method:     params.Get("method"),
+		EntityType: params.Get("entity_type"),
+		EntityID:   params.Get("entity_id"),
+	}
+
+	var err error
+
+	if query.Limit, err = parseIntParam(params, "limit"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid audit query", "error", err)
+		return
+	}
+
+	if query.Offset, err = parseIntParam(params, "offset"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid audit query", "error", err)
+		return
+	}
+
+	if query.From, err = parseDateParam(params, "from"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid audit query", "error", err)
+		return
+	}
+
+	if query.To, err = parseDateParam(params, "to"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid audit query", "error", err)
+		return
+	}
+
+	list, err := h.service.ListAudit(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list audit log", "error", err)
+		return
+	}
+
+	if list.Offset+len(list.Entries) < list.Total {
+		list.Next = nextPageLink(r, list.Limit, list.Offset+list.Limit, "")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
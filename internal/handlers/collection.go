@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type CollectionHandler struct {
+	service service.CollectionService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewCollectionHandler(service service.CollectionService, log *slog.Logger, env string) *CollectionHandler {
+	return &CollectionHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers collection routes, reads are public and writes require authenticated caller
+func (h *CollectionHandler) RegisterRoutes(router *mux.Router) {
+	writes := router.NewRoute().Subrouter()
+	writes.Use(middleware.RequireAuth)
+	writes.HandleFunc("/collections", h.CreateCollection).Methods("POST")
+	writes.HandleFunc("/collections/{slug}", h.UpdateCollection).Methods("PUT")
+	writes.HandleFunc("/collections/{slug}", h.DeleteCollection).Methods("DELETE")
+	writes.HandleFunc("/collections/{slug}/movies", h.AddCollectionMovie).Methods("POST")
+	writes.HandleFunc("/collections/{slug}/movies/{movieId}", h.MoveCollectionMovie).Methods("PUT")
+	writes.HandleFunc("/collections/{slug}/movies/{movieId}", h.RemoveCollectionMovie).Methods("DELETE")
+
+	router.HandleFunc("/collections", h.ListCollections).Methods("GET")
+	router.HandleFunc("/collections/{slug}", h.GetCollection).Methods("GET")
+	router.HandleFunc("/collections/{slug}/movies", h.ListCollectionMovies).Methods("GET")
+	router.HandleFunc("/movies/{id}/collections", h.ListMovieCollections).Methods("GET")
+}
+
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	var collection models.Collection
+
+	if err := decodeJSON(r, &collection); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode collection body", "error", err)
+		return
+	}
+
+	if err := h.service.CreateCollection(r.Context(), &collection); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to create collection", "error", err)
+		return
+	}
+
+	h.log.Info("Collection created succesfully", "ID", collection.ID, "slug", collection.Slug)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(collection)
+}
+
+func (h *CollectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	collection, err := h.service.GetCollection(r.Context(), slug)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get collection", "slug", slug, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(collection)
+}
+
+func (h *CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	var collection models.Collection
+	if err := decodeJSON(r, &collection); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode collection body", "error", err)
+		return
+	}
+
+	updated, err := h.service.UpdateCollection(r.Context(), slug, &collection)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to update collection", "slug", slug, "error", err)
+		return
+	}
+
+	h.log.Info("Collection updated succesfully", "slug", updated.Slug)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	if err := h.service.DeleteCollection(r.Context(), slug); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete collection", "slug", slug, "error", err)
+		return
+	}
+
+	h.log.Info("Collection was deleted succesfully", "slug", slug)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CollectionHandler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := &models.CollectionQuery{}
+
+	var err error
+
+	if query.Limit, err = parseIntParam(params, "limit"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid collections query", "error", err)
+		return
+	}
+
+	if query.Offset, err = parseIntParam(params, "offset"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid collections query", "error", err)
+		return
+	}
+
+	list, err := h.service.ListCollections(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list collections", "error", err)
+		return
+	}
+
+	if list.Offset+len(list.Collections) < list.Total {
+		list.Next = nextPageLink(r, list.Limit, list.Offset+list.Limit, "")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *CollectionHandler) ListMovieCollections(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	collections, err := h.service.ListMovieCollections(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list movie collections", "ID", id, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(collections)
+}
+
+func (h *CollectionHandler) ListCollectionMovies(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	movies, err := h.service.ListCollectionMovies(r.Context(), slug)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list collection movies", "slug", slug, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movies)
+}
+
+func (h *CollectionHandler) AddCollectionMovie(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	var placement models.CollectionPlacement
+	if err := decodeJSON(r, &placement); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode placement body", "error", err)
+		return
+	}
+
+	item, err := h.service.AddCollectionMovie(r.Context(), slug, &placement)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to add movie to collection", "slug", slug, "error", err)
+		return
+	}
+
+	h.log.Info("Movie added to collection succesfully", "slug", slug, "movie", item.MovieID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *CollectionHandler) MoveCollectionMovie(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	slug := vars["slug"]
+
+	movieID, err := strconv.Atoi(vars["movieId"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	var placement models.CollectionPlacement
+	if err := decodeJSON(r, &placement); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode placement body", "error", err)
+		return
+	}
+
+	placement.MovieID = movieID
+
+	item, err := h.service.MoveCollectionMovie(r.Context(), slug, &placement)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to move movie in collection", "slug", slug, "movie", movieID, "error", err)
+		return
+	}
+
+	h.log.Info("Movie moved in collection succesfully", "slug", slug, "movie", movieID, "index", item.Index)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *CollectionHandler) RemoveCollectionMovie(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	slug := vars["slug"]
+
+	movieID, err := strconv.Atoi(vars["movieId"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	if err := h.service.RemoveCollectionMovie(r.Context(), slug, movieID); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to remove movie from collection", "slug", slug, "movie", movieID, "error", err)
+		return
+	}
+
+	h.log.Info("Movie was removed from collection succesfully", "slug", slug, "movie", movieID)
+	w.WriteHeader(http.StatusNoContent)
+}
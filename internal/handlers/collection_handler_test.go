@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// memoryCollectionRepo keeps members as plain ordered slices, movies 1 to 3 exist
+type memoryCollectionRepo struct {
+	collections []*models.Collection
+	members     map[int][]int
+}
+
+func (r *memoryCollectionRepo) Create(ctx context.Context, c *models.Collection) error {
+	for _, existing := range r.collections {
+		if existing.Slug == c.Slug {
+			return models.ErrConflict
+		}
+	}
+
+	c.ID = len(r.collections) + 1
+	r.collections = append(r.collections, c)
+	return nil
+}
+
+func (r *memoryCollectionRepo) GetBySlug(ctx context.Context, slug string) (*models.Collection, error) {
+	for _, c := range r.collections {
+		if c.Slug == slug {
+			copied := *c
+			copied.MovieCount = len(r.members[c.ID])
+			return &copied, nil
+		}
+	}
+
+	return nil, models.ErrNotFound
+}
+
+func (r *memoryCollectionRepo) Update(ctx context.Context, slug string, c *models.Collection) (*models.Collection, error) {
+	existing, err := r.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ID = existing.ID
+	r.collections[c.ID-1] = c
+	return c, nil
+}
+
+func (r *memoryCollectionRepo) Delete(ctx context.Context, slug string) error {
+	return nil
+}
+
+func (r *memoryCollectionRepo) List(ctx context.Context, q *models.CollectionQuery) ([]*models.Collection, int, error) {
+	collections := []*models.Collection{}
+	for _, c := range r.collections {
+		if c.Public || q.IncludePrivate {
+			collections = append(collections, c)
+		}
+	}
+
+	return collections, len(collections), nil
+}
+
+func (r *memoryCollectionRepo) ListByMovie(ctx context.Context, movieID int, includePrivate bool) ([]*models.Collection, error) {
+	return []*models.Collection{}, nil
+}
+
+func (r *memoryCollectionRepo) ListMovies(ctx context.Context, collectionID int) ([]*models.CollectionMovie, error) {
+	items := []*models.CollectionMovie{}
+	for i, movieID := range r.members[collectionID] {
+		items = append(items, &models.CollectionMovie{MovieID: movieID, Index: i})
+	}
+
+	return items, nil
+}
+
+func (r *memoryCollectionRepo) AddMovie(ctx context.Context, collectionID int, p *models.CollectionPlacement) (*models.CollectionMovie, error) {
+	if p.MovieID < 1 || p.MovieID > 3 {
+		return nil, models.ErrNotFound
+	}
+
+	index := len(r.members[collectionID])
+	if p.Index != nil && *p.Index < index {
+		index = *p.Index
+	}
+
+	r.members[collectionID] = append(r.members[collectionID][:index], append([]int{p.MovieID}, r.members[collectionID][index:]...)...)
+	return &models.CollectionMovie{MovieID: p.MovieID, Index: index}, nil
+}
+
+func (r *memoryCollectionRepo) MoveMovie(ctx context.Context, collectionID, movieID, index int) (*models.CollectionMovie, error) {
+	if err := r.RemoveMovie(ctx, collectionID, movieID); err != nil {
+		return nil, err
+	}
+
+	return r.AddMovie(ctx, collectionID, &models.CollectionPlacement{MovieID: movieID, Index: &index})
+}
+
+func (r *memoryCollectionRepo) RemoveMovie(ctx context.Context, collectionID, movieID int) error {
+	for i, id := range r.members[collectionID] {
+		if id == movieID {
+			r.members[collectionID] = append(r.members[collectionID][:i], r.members[collectionID][i+1:]...)
+			return nil
+		}
+	}
+
+	return models.ErrNotFound
+}
+
+func TestCollectionHandler_Collections(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "jwt-secret"}
+	logger := logger.NewLogger("local")
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{"editor": models.RoleEditor}, logger))
+	collectionService := service.NewCollectionService(&memoryCollectionRepo{members: map[int][]int{}})
+	NewCollectionHandler(collectionService, logger, "local").RegisterRoutes(router)
+
+	editor := signToken(t, "jwt-secret", "editor", time.Minute)
+	viewer := signToken(t, "jwt-secret", "viewer", time.Minute)
+
+	body := `{"slug": "nolan-marathon", "name": "Nolan marathon", "cover_url": "https://example.com/nolan.jpg"}`
+
+	if w := serveJSON(router, "POST", "/collections", body, viewer); w.Code != http.StatusForbidden {
+		t.Errorf("Expected viewer to be forbidden, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/collections", `{"slug": "Best of 1994", "name": "Best", "cover_url": "ftp://example.com"}`, editor); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected invalid slug and cover to be rejected, got %d", w.Code)
+	}
+
+	w := serveJSON(router, "POST", "/collections", body, editor)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created models.Collection
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.CreatedBy != "editor" || created.Public {
+		t.Errorf("Expected private collection created by editor, got %+v", created)
+	}
+
+	if w := serveJSON(router, "GET", "/collections/nolan-marathon", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected private collection to be hidden, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "GET", "/collections/nolan-marathon", "", editor); w.Code != http.StatusOK {
+		t.Errorf("Expected editor to see private collection, got %d", w.Code)
+	}
+
+	for _, movieID := range []string{"1", "2", "3"} {
+		if w := serveJSON(router, "POST", "/collections/nolan-marathon/movies", `{"movie_id": `+movieID+`}`, editor); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	if w := serveJSON(router, "PUT", "/collections/nolan-marathon/movies/3", `{}`, editor); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected move without index to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/collections/nolan-marathon/movies/3", `{"index": -1}`, editor); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected negative index to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/collections/nolan-marathon/movies/3", `{"index": 0}`, editor); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body = `{"slug": "nolan-marathon", "name": "Nolan marathon", "public": true}`
+	if w := serveJSON(router, "PUT", "/collections/nolan-marathon", body, editor); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = serveJSON(router, "GET", "/collections/nolan-marathon/movies", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected public collection to be visible, got %d", w.Code)
+	}
+
+	var movies []*models.CollectionMovie
+	if err := json.NewDecoder(w.Body).Decode(&movies); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(movies) != 3 || movies[0].MovieID != 3 || movies[1].MovieID != 1 || movies[2].MovieID != 2 {
+		t.Errorf("Expected movies 3, 1, 2, got %+v", movies)
+	}
+}
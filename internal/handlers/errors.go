@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/problem"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+)
+
+const envProd = "prod"
+
+// errorWriter answers errors with problem details, it's embedded into every handler
+type errorWriter struct {
+	// hideInternal keeps details of internal errors out of responses, they are only logged
+	hideInternal bool
+}
+
+func newErrorWriter(env string) errorWriter {
+	return errorWriter{hideInternal: env == envProd}
+}
+
+// problemFromError maps domain errors to problem type and HTTP status, unknown errors are internal
+func problemFromError(err error) (string, int) {
+	switch {
+	case errors.Is(err, models.ErrBadRequest):
+		return problem.TypeBadRequest, http.StatusBadRequest
+	case errors.Is(err, models.ErrNotFound):
+		return problem.TypeNotFound, http.StatusNotFound
+	case errors.Is(err, models.ErrConflict):
+		return problem.TypeConflict, http.StatusConflict
+	case errors.Is(err, models.ErrValidation):
+		return problem.TypeValidation, http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrUnsupportedMediaType):
+		return problem.TypeUnsupportedMediaType, http.StatusUnsupportedMediaType
+	case errors.Is(err, models.ErrPreconditionFailed):
+		return problem.TypePreconditionFailed, http.StatusPreconditionFailed
+	case errors.Is(err, models.ErrUnauthorized):
+		return problem.TypeUnauthorized, http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return problem.TypeForbidden, http.StatusForbidden
+	default:
+		return problem.TypeInternal, http.StatusInternalServerError
+	}
+}
+
+// writeError answers with problem matching err
+func (e errorWriter) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	typ, status := problemFromError(err)
+
+	detail := err.Error()
+	if status == http.StatusInternalServerError && e.hideInternal {
+		detail = ""
+	}
+
+	// errors like constraint violations carry detail for clients, the full message is only logged
+	var public interface{ PublicDetail() string }
+	if errors.As(err, &public) {
+		detail = public.PublicDetail()
+	}
+
+	p := problem.New(typ, status, detail)
+	p.Instance = r.URL.Path
+	p.RequestID = middleware.GetReqID(r.Context())
+
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		p.Detail = "request has invalid parameters"
+		for _, f := range verr.Fields {
+			p.InvalidParams = append(p.InvalidParams, problem.InvalidParam{
+				Name:   f.Field,
+				Code:   f.Code,
+				Reason: f.Message,
+			})
+		}
+	}
+
+	problem.Write(w, p)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+// movieETag returns strong entity tag of the movie version. Rating stats don't change the version,
+// so tags of rated movies also carry hash of the score histogram, like "3.9f0c41a2"
+func movieETag(movie *models.Movie) string {
+	tag := strconv.Itoa(movie.Version)
+
+	if movie.Rating.Count > 0 {
+		h := fnv.New32a()
+		for _, count := range movie.Rating.Histogram {
+			fmt.Fprintf(h, "%d,", count)
+		}
+		tag += fmt.Sprintf(".%08x", h.Sum32())
+	}
+
+	return `"` + tag + `"`
+}
+
+// ifMatchVersion returns version required by If-Match header, it's zero when header is absent or "*".
+// Only single strong entity tag is supported, anything else can't match. Rating part of the tag is
+// ignored, ratings aren't written through the movie, so they can't be lost by its update
+func ifMatchVersion(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		tag, ok = strings.CutSuffix(tag, `"`)
+	}
+
+	tag, _, _ = strings.Cut(tag, ".")
+
+	version, err := strconv.Atoi(tag)
+	if !ok || err != nil || version <= 0 {
+		return 0, fmt.Errorf("If-Match %s doesn't match any version: %w", header, models.ErrPreconditionFailed)
+	}
+
+	return version, nil
+}
+
+// ifNoneMatch reports whether If-None-Match header lists etag, comparison is weak as RFC 9110 requires
+func ifNoneMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for tag := range strings.SplitSeq(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
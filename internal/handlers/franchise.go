@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type FranchiseHandler struct {
+	service service.FranchiseService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewFranchiseHandler(service service.FranchiseService, log *slog.Logger, env string) *FranchiseHandler {
+	return &FranchiseHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers franchise routes, reads are public and writes require authenticated caller
+func (h *FranchiseHandler) RegisterRoutes(router *mux.Router) {
+	writes := router.NewRoute().Subrouter()
+	writes.Use(middleware.RequireAuth)
+	writes.HandleFunc("/franchises", h.CreateFranchise).Methods("POST")
+	writes.HandleFunc("/franchises/{slug}", h.UpdateFranchise).Methods("PUT")
+	writes.HandleFunc("/franchises/{slug}", h.DeleteFranchise).Methods("DELETE")
+	writes.HandleFunc("/franchises/{slug}/movies", h.SetFranchiseMovies).Methods("PUT")
+
+	router.HandleFunc("/franchises", h.ListFranchises).Methods("GET")
+	router.HandleFunc("/franchises/{slug}", h.GetFranchise).Methods("GET")
+}
+
+func (h *FranchiseHandler) CreateFranchise(w http.ResponseWriter, r *http.Request) {
+	var franchise models.Franchise
+
+	if err := decodeJSON(r, &franchise); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode franchise body", "error", err)
+		return
+	}
+
+	if err := h.service.CreateFranchise(r.Context(), &franchise); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to create franchise", "error", err)
+		return
+	}
+
+	h.log.Info("Franchise created succesfully", "ID", franchise.ID, "slug", franchise.Slug)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(franchise)
+}
+
+func (h *FranchiseHandler) GetFranchise(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	franchise, err := h.service.GetFranchise(r.Context(), slug)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get franchise", "slug", slug, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(franchise)
+}
+
+func (h *FranchiseHandler) UpdateFranchise(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	var franchise models.Franchise
+	if err := decodeJSON(r, &franchise); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode franchise body", "error", err)
+		return
+	}
+
+	updated, err := h.service.UpdateFranchise(r.Context(), slug, &franchise)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to update franchise", "slug", slug, "error", err)
+		return
+	}
+
+	h.log.Info("Franchise updated succesfully", "slug", updated.Slug)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *FranchiseHandler) DeleteFranchise(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	if err := h.service.DeleteFranchise(r.Context(), slug); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete franchise", "slug", slug, "error", err)
+		return
+	}
+
+	h.log.Info("Franchise was deleted succesfully", "slug", slug)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FranchiseHandler) ListFranchises(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := &models.FranchiseQuery{}
+
+	var err error
+
+	if query.Limit, err = parseIntParam(params, "limit"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid franchises query", "error", err)
+		return
+	}
+
+	if query.Offset, err = parseIntParam(params, "offset"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid franchises query", "error", err)
+		return
+	}
+
+	list, err := h.service.ListFranchises(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list franchises", "error", err)
+		return
+	}
+
+	if list.Offset+len(list.Franchises) < list.Total {
+		list.Next = nextPageLink(r, list.Limit, list.Offset+list.Limit, "")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *FranchiseHandler) SetFranchiseMovies(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	var members models.FranchiseMembers
+	if err := decodeJSON(r, &members); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode franchise members body", "error", err)
+		return
+	}
+
+	movies, err := h.service.SetFranchiseMovies(r.Context(), slug, &members)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to set franchise movies", "slug", slug, "error", err)
+		return
+	}
+
+	h.log.Info("Franchise movies set succesfully", "slug", slug, "count", len(movies))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movies)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// memoryFranchiseRepo keeps members as plain ordered slices, movies 1 to 3 exist
+type memoryFranchiseRepo struct {
+	franchises []*models.Franchise
+	members    map[int][]int
+}
+
+func (r *memoryFranchiseRepo) withMovies(f *models.Franchise) *models.Franchise {
+	copied := *f
+	copied.Movies = []*models.FranchiseMovie{}
+	for i, movieID := range r.members[f.ID] {
+		copied.Movies = append(copied.Movies, &models.FranchiseMovie{MovieID: movieID, Position: i})
+	}
+
+	return &copied
+}
+
+func (r *memoryFranchiseRepo) Create(ctx context.Context, f *models.Franchise) error {
+	for _, existing := range r.franchises {
+		if existing.Slug == f.Slug {
+			return models.ErrConflict
+		}
+	}
+
+	f.ID = len(r.franchises) + 1
+	r.franchises = append(r.franchises, f)
+	return nil
+}
+
+func (r *memoryFranchiseRepo) GetBySlug(ctx context.Context, slug string) (*models.Franchise, error) {
+	for _, f := range r.franchises {
+		if f.Slug == slug {
+			return r.withMovies(f), nil
+		}
+	}
+
+	return nil, models.ErrNotFound
+}
+
+func (r *memoryFranchiseRepo) Update(ctx context.Context, slug string, f *models.Franchise) (*models.Franchise, error) {
+	existing, err := r.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	f.ID = existing.ID
+	r.franchises[f.ID-1] = f
+	return r.withMovies(f), nil
+}
+
+func (r *memoryFranchiseRepo) Delete(ctx context.Context, slug string) error {
+	return nil
+}
+
+func (r *memoryFranchiseRepo) List(ctx context.Context, q *models.FranchiseQuery) ([]*models.Franchise, int, error) {
+	franchises := []*models.Franchise{}
+	for _, f := range r.franchises {
+		franchises = append(franchises, r.withMovies(f))
+	}
+
+	return franchises, len(franchises), nil
+}
+
+func (r *memoryFranchiseRepo) ListByMovie(ctx context.Context, movieID int) ([]*models.Franchise, error) {
+	franchises := []*models.Franchise{}
+	for _, f := range r.franchises {
+		for _, id := range r.members[f.ID] {
+			if id == movieID {
+				franchises = append(franchises, r.withMovies(f))
+			}
+		}
+	}
+
+	return franchises, nil
+}
+
+func (r *memoryFranchiseRepo) SetMovies(ctx context.Context, franchiseID int, movieIDs []int) ([]*models.FranchiseMovie, error) {
+	for _, id := range movieIDs {
+		if id > 3 {
+			return nil, models.ErrNotFound
+		}
+	}
+
+	r.members[franchiseID] = movieIDs
+	return r.withMovies(r.franchises[franchiseID-1]).Movies, nil
+}
+
+func TestFranchiseHandler_Franchises(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "jwt-secret"}
+	logger := logger.NewLogger("local")
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{"editor": models.RoleEditor}, logger))
+	franchiseService := service.NewFranchiseService(&memoryFranchiseRepo{members: map[int][]int{}})
+	NewFranchiseHandler(franchiseService, logger, "local").RegisterRoutes(router)
+
+	editor := signToken(t, "jwt-secret", "editor", time.Minute)
+	viewer := signToken(t, "jwt-secret", "viewer", time.Minute)
+
+	body := `{"slug": "the-matrix", "name": "The Matrix"}`
+
+	if w := serveJSON(router, "POST", "/franchises", body, viewer); w.Code != http.StatusForbidden {
+		t.Errorf("Expected viewer to be forbidden, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/franchises", `{"slug": "The Matrix", "name": ""}`, editor); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected invalid slug and name to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/franchises", body, editor); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveJSON(router, "PUT", "/franchises/the-matrix/movies", `{"movie_ids": [1, 2, 1]}`, editor); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected duplicate movies to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/franchises/the-matrix/movies", `{"movie_ids": [1, 42]}`, editor); w.Code != http.StatusNotFound {
+		t.Errorf("Expected unknown movie to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/franchises/the-matrix/movies", `{"movie_ids": [3, 1, 2]}`, editor); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := serveJSON(router, "GET", "/franchises/the-matrix", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected franchise to be public, got %d", w.Code)
+	}
+
+	var franchise models.Franchise
+	if err := json.NewDecoder(w.Body).Decode(&franchise); err != nil {
+		t.Fatal(err)
+	}
+
+	movies := franchise.Movies
+	if len(movies) != 3 || movies[0].MovieID != 3 || movies[1].MovieID != 1 || movies[2].MovieID != 2 {
+		t.Errorf("Expected movies 3, 1, 2, got %+v", movies)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type GenreHandler struct {
+	service service.GenreService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewGenreHandler(service service.GenreService, log *slog.Logger, env string) *GenreHandler {
+	return &GenreHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers genre routes, reads are public and writes require authenticated caller
+func (h *GenreHandler) RegisterRoutes(router *mux.Router) {
+	writes := router.NewRoute().Subrouter()
+	writes.Use(middleware.RequireAuth)
+	writes.HandleFunc("/genres", h.CreateGenre).Methods("POST")
+	writes.HandleFunc("/genres/{slug}", h.UpdateGenre).Methods("PUT")
+	writes.HandleFunc("/genres/{slug}", h.DeleteGenre).Methods("DELETE")
+
+	router.HandleFunc("/genres", h.ListGenres).Methods("GET")
+	router.HandleFunc("/genres/{slug}", h.GetGenre).Methods("GET")
+}
+
+func (h *GenreHandler) CreateGenre(w http.ResponseWriter, r *http.Request) {
+	var genre models.Genre
+
+	if err := decodeJSON(r, &genre); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode genre body", "error", err)
+		return
+	}
+
+	if err := h.service.CreateGenre(r.Context(), &genre); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to create genre", "error", err)
+		return
+	}
+
+	h.log.Info("Genre created succesfully", "slug", genre.Slug)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(genre)
+}
+
+func (h *GenreHandler) GetGenre(w http.ResponseWriter, r *http.Request) {
+	genre, err := h.service.GetGenre(r.Context(), mux.Vars(r)["slug"])
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get genre", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(genre)
+}
+
+func (h *GenreHandler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	var genre models.Genre
+	if err := decodeJSON(r, &genre); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode genre body", "error", err)
+		return
+	}
+
+	updated, err := h.service.UpdateGenre(r.Context(), slug, &genre)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to update genre", "slug", slug, "error", err)
+		return
+	}
+
+	h.log.Info("Genre updated succesfully", "slug", updated.Slug)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *GenreHandler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	slug := mux.Vars(r)["slug"]
+
+	if err := h.service.DeleteGenre(r.Context(), slug); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete genre", "error", err)
+		return
+	}
+
+	h.log.Info("Genre was deleted succesfully", "slug", slug)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GenreHandler) ListGenres(w http.ResponseWriter, r *http.Request) {
+	genres, err := h.service.ListGenres(r.Context())
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list genres", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(genres)
+}
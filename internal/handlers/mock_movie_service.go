@@ -3,6 +3,8 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -10,6 +12,8 @@ import (
 	"github.com/CAATHARSIS/movies-library/internal/service"
 )
 
+// MockMovieService stores movies as they are sent, it doesn't validate, version or keep history of them.
+// Tests which depend on that run movie service on memoryMovieRepo
 type MockMovieService struct {
 	movies  map[int]*models.Movie
 	nextID  int
@@ -52,7 +56,7 @@ func (m *MockMovieService) GetMovie(ctx context.Context, id int) (*models.Movie,
 
 	movie, exists := m.movies[id]
 	if !exists {
-		return nil, errors.New("movie not found")
+		return nil, fmt.Errorf("movie %d: %w", id, models.ErrNotFound)
 	}
 
 	return movie, nil
@@ -68,35 +72,26 @@ func (m *MockMovieService) UpdateMovie(ctx context.Context, movie *models.Movie)
 
 	old_movie, exists := m.movies[movie.ID]
 	if !exists {
-		return nil, errors.New("movie not found")
+		return nil, fmt.Errorf("movie %d: %w", movie.ID, models.ErrNotFound)
 	}
 
-	if movie.Title != "" {
-		old_movie.Title = movie.Title
-	}
-
-	if movie.Director != "" {
-		old_movie.Director = movie.Director
-	}
-
-	if movie.ReleaseDate.IsZero() {
-		old_movie.ReleaseDate = movie.ReleaseDate
-	}
+	movie.CreatedAt = old_movie.CreatedAt
+	movie.UpdatedAt = time.Now()
+	m.movies[movie.ID] = movie
 
-	if movie.Genre != "" {
-		old_movie.Genre = movie.Genre
-	}
+	return movie, nil
+}
 
-	if movie.Description != "" {
-		old_movie.Description = movie.Description
+// PatchMovie returns the movie unchanged
+func (m *MockMovieService) PatchMovie(ctx context.Context, id, version int, patchType string, patch []byte) (*models.Movie, error) {
+	if m.ErrorOn["PatchMovie"] {
+		return nil, errors.New("mock patch movie error")
 	}
 
-	old_movie.UpdatedAt = time.Now()
-
-	return old_movie, nil
+	return m.GetMovie(ctx, id)
 }
 
-func (m *MockMovieService) DeleteMovie(ctx context.Context, id int) error {
+func (m *MockMovieService) DeleteMovie(ctx context.Context, id, version int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -104,11 +99,40 @@ func (m *MockMovieService) DeleteMovie(ctx context.Context, id int) error {
 		return errors.New("mock delete movie error")
 	}
 
+	if _, exists := m.movies[id]; !exists {
+		return fmt.Errorf("movie %d: %w", id, models.ErrNotFound)
+	}
+
 	delete(m.movies, id)
 	return nil
 }
 
-func (m *MockMovieService) ListMovies(ctx context.Context) ([]*models.Movie, error) {
+func (m *MockMovieService) ListDeletedMovies(ctx context.Context, limit, offset int) (*models.MovieList, error) {
+	return &models.MovieList{Movies: []*models.Movie{}, Limit: limit, Offset: offset}, nil
+}
+
+func (m *MockMovieService) RestoreMovie(ctx context.Context, id int) (*models.Movie, error) {
+	return nil, fmt.Errorf("deleted movie %d: %w", id, models.ErrNotFound)
+}
+
+func (m *MockMovieService) PurgeMovie(ctx context.Context, id int) error {
+	return fmt.Errorf("deleted movie %d: %w", id, models.ErrNotFound)
+}
+
+func (m *MockMovieService) GetMovieAt(ctx context.Context, id int, asOf time.Time) (*models.Movie, error) {
+	return nil, fmt.Errorf("movie %d as of %s: %w", id, asOf, models.ErrNotFound)
+}
+
+func (m *MockMovieService) GetMovieHistory(ctx context.Context, id int) ([]*models.Revision, error) {
+	return nil, fmt.Errorf("movie %d: %w", id, models.ErrNotFound)
+}
+
+func (m *MockMovieService) RevertMovie(ctx context.Context, id, revisionID, version int) (*models.Movie, error) {
+	return nil, fmt.Errorf("revision %d: %w", revisionID, models.ErrNotFound)
+}
+
+// ListMovies returns every movie ordered by id on one page
+func (m *MockMovieService) ListMovies(ctx context.Context, q *models.MovieQuery) (*models.MovieList, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -116,12 +140,33 @@ func (m *MockMovieService) ListMovies(ctx context.Context) ([]*models.Movie, err
 		return nil, errors.New("mock list movies error")
 	}
 
-	var movies []*models.Movie
+	list := &models.MovieList{Movies: []*models.Movie{}, Limit: q.Limit, Offset: q.Offset}
 	for _, movie := range m.movies {
-		movies = append(movies, movie)
+		list.Movies = append(list.Movies, movie)
+	}
+
+	sort.Slice(list.Movies, func(i, j int) bool {
+		return list.Movies[i].ID < list.Movies[j].ID
+	})
+	list.Total = len(list.Movies)
+
+	return list, nil
+}
+
+func (m *MockMovieService) SearchMovies(ctx context.Context, q *models.SearchQuery) (*models.SearchResults, error) {
+	if m.ErrorOn["SearchMovies"] {
+		return nil, errors.New("mock search movies error")
+	}
+
+	return &models.SearchResults{Results: []*models.SearchResult{}, Limit: q.Limit, Offset: q.Offset}, nil
+}
+
+func (m *MockMovieService) SuggestMovies(ctx context.Context, text string, limit int) ([]*models.Suggestion, error) {
+	if m.ErrorOn["SuggestMovies"] {
+		return nil, errors.New("mock suggest movies error")
 	}
 
-	return movies, nil
+	return []*models.Suggestion{}, nil
 }
 
 func (m *MockMovieService) AddTestMovies(movies ...*models.Movie) {
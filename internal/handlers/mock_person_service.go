@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+)
+
+type MockPersonService struct {
+	people  map[int]*models.Person
+	credits map[int]*models.Credit
+	nextID  int
+	mu      sync.RWMutex
+	ErrorOn map[string]bool
+}
+
+func NewMockPersonService() service.PersonService {
+	return &MockPersonService{
+		people:  make(map[int]*models.Person),
+		credits: make(map[int]*models.Credit),
+		nextID:  1,
+		ErrorOn: make(map[string]bool),
+	}
+}
+
+func (m *MockPersonService) CreatePerson(ctx context.Context, person *models.Person) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ErrorOn["CreatePerson"] {
+		return errors.New("mock create person error")
+	}
+
+	person.ID = m.nextID
+	m.nextID++
+	person.CreatedAt = time.Now()
+	person.UpdatedAt = time.Now()
+	m.people[person.ID] = person
+
+	return nil
+}
+
+func (m *MockPersonService) GetPerson(ctx context.Context, id int) (*models.Person, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	person, exists := m.people[id]
+	if !exists {
+		return nil, fmt.Errorf("person %d: %w", id, models.ErrNotFound)
+	}
+
+	return person, nil
+}
+
+func (m *MockPersonService) UpdatePerson(ctx context.Context, person *models.Person) (*models.Person, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, exists := m.people[person.ID]
+	if !exists {
+		return nil, fmt.Errorf("person %d: %w", person.ID, models.ErrNotFound)
+	}
+
+	person.CreatedAt = old.CreatedAt
+	person.UpdatedAt = time.Now()
+	m.people[person.ID] = person
+
+	return person, nil
+}
+
+func (m *MockPersonService) DeletePerson(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.people, id)
+	return nil
+}
+
+func (m *MockPersonService) ListPeople(ctx context.Context, q *models.PersonQuery) (*models.PersonList, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := &models.PersonList{People: []*models.Person{}, Limit: q.Limit, Offset: q.Offset}
+	for _, person := range m.people {
+		list.People = append(list.People, person)
+	}
+
+	sort.Slice(list.People, func(i, j int) bool {
+		return list.People[i].ID < list.People[j].ID
+	})
+	list.Total = len(list.People)
+
+	return list, nil
+}
+
+func (m *MockPersonService) GetFilmography(ctx context.Context, personID int) ([]*models.Credit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, exists := m.people[personID]; !exists {
+		return nil, fmt.Errorf("person %d: %w", personID, models.ErrNotFound)
+	}
+
+	credits := []*models.Credit{}
+	for _, credit := range m.credits {
+		if credit.PersonID == personID {
+			credits = append(credits, credit)
+		}
+	}
+
+	return credits, nil
+}
+
+func (m *MockPersonService) AddCredit(ctx context.Context, credit *models.Credit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if credit.Role != models.CreditDirector && credit.Role != models.CreditWriter &&
+		credit.Role != models.CreditActor && credit.Role != models.CreditComposer {
+		v := &service.ValidationError{}
+		v.Add("role", service.CodeInvalid, "invalid credit role")
+		return v
+	}
+
+	credit.ID = m.nextID
+	m.nextID++
+	m.credits[credit.ID] = credit
+
+	return nil
+}
+
+func (m *MockPersonService) DeleteCredit(ctx context.Context, movieID, creditID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if credit, exists := m.credits[creditID]; exists && credit.MovieID == movieID {
+		delete(m.credits, creditID)
+	}
+
+	return nil
+}
+
+func (m *MockPersonService) ListMovieCredits(ctx context.Context, movieID int) ([]*models.Credit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	credits := []*models.Credit{}
+	for _, credit := range m.credits {
+		if credit.MovieID == movieID {
+			credits = append(credits, credit)
+		}
+	}
+
+	return credits, nil
+}
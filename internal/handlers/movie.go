@@ -2,48 +2,70 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
 	"github.com/CAATHARSIS/movies-library/internal/models"
 	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/CAATHARSIS/movies-library/pkg/iso"
 	"github.com/gorilla/mux"
 )
 
 type MovieHandler struct {
 	service service.MovieService
 	log     *slog.Logger
+	errorWriter
 }
 
-func NewMovieHandler(service service.MovieService, log *slog.Logger) *MovieHandler {
-	return &MovieHandler{service: service, log: log}
+func NewMovieHandler(service service.MovieService, log *slog.Logger, env string) *MovieHandler {
+	return &MovieHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
 }
 
+// RegisterRoutes registers movie routes, reads are public, while writes and reads of trash require authenticated caller
 func (h *MovieHandler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/movies", h.CreateMovie).Methods("POST")
+	writes := router.NewRoute().Subrouter()
+	writes.Use(middleware.RequireAuth)
+	writes.HandleFunc("/movies/trash", h.ListDeletedMovies).Methods("GET")
+	writes.HandleFunc("/movies", h.CreateMovie).Methods("POST")
+	writes.HandleFunc("/movies/{id}/restore", h.RestoreMovie).Methods("POST")
+	writes.HandleFunc("/movies/{id}/history/{revision}/revert", h.RevertMovie).Methods("POST")
+	writes.HandleFunc("/movies/{id}", h.UpdateMovie).Methods("PUT")
+	writes.HandleFunc("/movies/{id}", h.PatchMovie).Methods("PATCH")
+	writes.HandleFunc("/movies/{id}", h.DeleteMovie).Methods("DELETE")
+	writes.HandleFunc("/admin/movies/{id}", h.PurgeMovie).Methods("DELETE")
+
+	router.HandleFunc("/movies/search", h.SearchMovies).Methods("GET")
+	router.HandleFunc("/movies/suggest", h.SuggestMovies).Methods("GET")
+	router.HandleFunc("/movies/{id}/history", h.GetMovieHistory).Methods("GET")
 	router.HandleFunc("/movies/{id}", h.GetMovie).Methods("GET")
-	router.HandleFunc("/movies/{id}", h.UpdateMovie).Methods("PUT")
-	router.HandleFunc("/movies/{id}", h.DeleteMovie).Methods("DELETE")
 	router.HandleFunc("/movies", h.ListMovies).Methods("GET")
 }
 
 func (h *MovieHandler) CreateMovie(w http.ResponseWriter, r *http.Request) {
 	var movie models.Movie
 
-	if err := json.NewDecoder(r.Body).Decode(&movie); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := decodeJSON(r, &movie); err != nil {
+		h.writeError(w, r, err)
 		h.log.Error("Failed to decode movie body", "error", err)
 		return
 	}
 
 	if err := h.service.CreateMovie(r.Context(), &movie); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.writeError(w, r, err)
 		h.log.Error("Failed to create movie", "error", err)
 		return
 	}
 
 	h.log.Info("Movie created succesully", "ID", movie.ID)
+	w.Header().Set("ETag", movieETag(&movie))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(movie)
@@ -54,18 +76,39 @@ func (h *MovieHandler) GetMovie(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid movie id", http.StatusBadRequest)
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
 		h.log.Error("Invalid movie id", "ID", id)
 		return
 	}
 
-	movie, err := h.service.GetMovie(r.Context(), id)
+	asOf, err := parseDateParam(r.URL.Query(), "as_of")
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid as_of", "error", err)
+		return
+	}
+
+	var movie *models.Movie
+	if asOf != nil {
+		movie, err = h.service.GetMovieAt(r.Context(), id, *asOf)
+	} else {
+		movie, err = h.service.GetMovie(r.Context(), id)
+	}
+
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.writeError(w, r, err)
 		h.log.Error("Failed to get movie", "error", err)
 		return
 	}
 
+	etag := movieETag(movie)
+	w.Header().Set("ETag", etag)
+
+	if ifNoneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	h.log.Info("Movie got", "ID", movie.ID)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(movie)
@@ -75,42 +118,103 @@ func (h *MovieHandler) UpdateMovie(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid movie id", http.StatusBadRequest)
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
 		h.log.Error("Invalid movie ID", "error", err)
 		return
 	}
 
 	var movie models.Movie
-	if err := json.NewDecoder(r.Body).Decode(&movie); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+
+	if err := decodeJSON(r, &movie); err != nil {
+		h.writeError(w, r, err)
 		h.log.Error("Failed to decode json body", "error", err)
 		return
 	}
 	movie.ID = id
 
+	// version from If-Match wins over the one sent in body, body version is used without the header
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid If-Match", "ID", id, "error", err)
+		return
+	}
+
+	if version != 0 {
+		movie.Version = version
+	}
+
 	updatedMovie, err := h.service.UpdateMovie(r.Context(), &movie)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.writeError(w, r, err)
 		h.log.Error("Failed to update movie", "ID", movie.ID)
 		return
 	}
 
 	h.log.Info("Movie updated succesfully", "ID", movie.ID)
+	w.Header().Set("ETag", movieETag(updatedMovie))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(updatedMovie)
 }
 
+func (h *MovieHandler) PatchMovie(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie ID", "error", err)
+		return
+	}
+
+	w.Header().Set("Accept-Patch", service.MergePatchType+", "+service.JSONPatchType)
+
+	// parameters such as charset are ignored, patch is always UTF-8 JSON
+	patchType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: failed to read body: %v", models.ErrBadRequest, err))
+		h.log.Error("Failed to read patch body", "error", err)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid If-Match", "ID", id, "error", err)
+		return
+	}
+
+	movie, err := h.service.PatchMovie(r.Context(), id, version, patchType, patch)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to patch movie", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Movie patched succesfully", "ID", id)
+	w.Header().Set("ETag", movieETag(movie))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movie)
+}
+
 func (h *MovieHandler) DeleteMovie(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid movie id", http.StatusBadRequest)
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
 		h.log.Error("Invalid movie id", "error", err)
 		return
 	}
 
-	if err := h.service.DeleteMovie(r.Context(), id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid If-Match", "ID", id, "error", err)
+		return
+	}
+
+	if err := h.service.DeleteMovie(r.Context(), id, version); err != nil {
+		h.writeError(w, r, err)
 		h.log.Error("Failed to delete movie", "error", err)
 		return
 	}
@@ -119,15 +223,394 @@ func (h *MovieHandler) DeleteMovie(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *MovieHandler) ListDeletedMovies(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	limit, err := parseIntParam(params, "limit")
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid trash query", "error", err)
+		return
+	}
+
+	offset, err := parseIntParam(params, "offset")
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid trash query", "error", err)
+		return
+	}
+
+	list, err := h.service.ListDeletedMovies(r.Context(), limit, offset)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list deleted movies", "error", err)
+		return
+	}
+
+	if list.Offset+len(list.Movies) < list.Total {
+		list.Next = nextPageLink(r, list.Limit, list.Offset+list.Limit, "")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *MovieHandler) RestoreMovie(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	movie, err := h.service.RestoreMovie(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to restore movie", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Movie restored succesfully", "ID", id)
+	w.Header().Set("ETag", movieETag(movie))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movie)
+}
+
+func (h *MovieHandler) GetMovieHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	history, err := h.service.GetMovieHistory(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get movie history", "ID", id, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+func (h *MovieHandler) RevertMovie(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	revisionID, err := strconv.Atoi(vars["revision"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid revision id", models.ErrBadRequest))
+		h.log.Error("Invalid revision id", "error", err)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid If-Match", "ID", id, "error", err)
+		return
+	}
+
+	movie, err := h.service.RevertMovie(r.Context(), id, revisionID, version)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to revert movie", "ID", id, "revision", revisionID, "error", err)
+		return
+	}
+
+	h.log.Info("Movie reverted succesfully", "ID", id, "revision", revisionID)
+	w.Header().Set("ETag", movieETag(movie))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movie)
+}
+
+func (h *MovieHandler) PurgeMovie(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	if err := h.service.PurgeMovie(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to purge movie", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Movie was purged", "ID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *MovieHandler) ListMovies(w http.ResponseWriter, r *http.Request) {
-	movies, err := h.service.ListMovies(r.Context())
+	query, err := parseMovieQuery(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid list query", "error", err)
+		return
+	}
+
+	list, err := h.service.ListMovies(r.Context(), query)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.writeError(w, r, err)
 		h.log.Error("Failed to list movies", "error", err)
 		return
 	}
 
+	if list.NextCursor != "" {
+		list.Next = nextPageLink(r, list.Limit, list.Offset+list.Limit, list.NextCursor)
+	}
+
 	h.log.Info("Movies listed succesfully")
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(movies)
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *MovieHandler) SearchMovies(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	query := &models.SearchQuery{Query: strings.TrimSpace(params.Get("q"))}
+	if query.Query == "" {
+		h.writeError(w, r, fmt.Errorf("%w: search query is required", models.ErrBadRequest))
+		h.log.Error("Empty search query")
+		return
+	}
+
+	var err error
+
+	if query.Limit, err = parseIntParam(params, "limit"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid search query", "error", err)
+		return
+	}
+
+	if query.Offset, err = parseIntParam(params, "offset"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid search query", "error", err)
+		return
+	}
+
+	results, err := h.service.SearchMovies(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to search movies", "error", err)
+		return
+	}
+
+	if results.Offset+len(results.Results) < results.Total {
+		results.Next = nextPageLink(r, results.Limit, results.Offset+results.Limit, "")
+	}
+
+	h.log.Info("Movies searched succesfully", "total", results.Total)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+func (h *MovieHandler) SuggestMovies(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	text := strings.TrimSpace(params.Get("q"))
+	if text == "" {
+		h.writeError(w, r, fmt.Errorf("%w: search query is required", models.ErrBadRequest))
+		h.log.Error("Empty suggest query")
+		return
+	}
+
+	limit, err := parseIntParam(params, "limit")
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid suggest query", "error", err)
+		return
+	}
+
+	suggestions, err := h.service.SuggestMovies(r.Context(), text, limit)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to suggest movies", "error", err)
+		return
+	}
+
+	h.log.Debug("Movies suggested", "count", len(suggestions))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestions)
+}
+
+var sortFields = map[string]bool{
+	models.SortByTitle:       true,
+	models.SortByReleaseDate: true,
+	models.SortByCreatedAt:   true,
+	models.SortByUpdatedAt:   true,
+	models.SortByRating:      true,
+}
+
+// parseMovieQuery reads pagination, sorting and filters from request query parameters
+func parseMovieQuery(r *http.Request) (*models.MovieQuery, error) {
+	params := r.URL.Query()
+	query := &models.MovieQuery{
+		Cursor:   params.Get("cursor"),
+		Genre:    params.Get("genre"),
+		Director: params.Get("director"),
+	}
+
+	var err error
+
+	if query.Limit, err = parseIntParam(params, "limit"); err != nil {
+		return nil, err
+	}
+
+	if query.Offset, err = parseIntParam(params, "offset"); err != nil {
+		return nil, err
+	}
+
+	if sort := params.Get("sort"); sort != "" {
+		if !sortFields[sort] {
+			return nil, fmt.Errorf("%w: invalid sort field %q", models.ErrBadRequest, sort)
+		}
+		query.SortBy = sort
+	}
+
+	switch order := params.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.SortDesc = true
+	default:
+		return nil, fmt.Errorf("%w: invalid sort order %q", models.ErrBadRequest, order)
+	}
+
+	if query.ReleasedFrom, err = parseDateParam(params, "released_from"); err != nil {
+		return nil, err
+	}
+
+	if query.ReleasedTo, err = parseDateParam(params, "released_to"); err != nil {
+		return nil, err
+	}
+
+	if tags := params.Get("tags"); tags != "" {
+		query.Tags = strings.Split(tags, ",")
+	}
+
+	switch mode := params.Get("tag_mode"); mode {
+	case "", "all":
+	case "any":
+		query.AnyTag = true
+	default:
+		return nil, fmt.Errorf("%w: invalid tag mode %q, use all or any", models.ErrBadRequest, mode)
+	}
+
+	if err := parseMetadataQuery(params, query); err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+// parseMetadataQuery fills filters of extended movie metadata, ISO codes are accepted in any case
+func parseMetadataQuery(params url.Values, query *models.MovieQuery) error {
+	query.OriginalTitle = params.Get("original_title")
+
+	codes := []struct {
+		name  string
+		dst   *string
+		valid func(string) bool
+		fold  func(string) string
+	}{
+		{"country", &query.Country, iso.IsCountry, strings.ToUpper},
+		{"language", &query.Language, iso.IsLanguage, strings.ToLower},
+		{"currency", &query.Currency, iso.IsCurrency, strings.ToUpper},
+	}
+
+	for _, c := range codes {
+		value := params.Get(c.name)
+		if value != "" && !c.valid(c.fold(value)) {
+			return fmt.Errorf("%w: invalid %s code %q", models.ErrBadRequest, c.name, value)
+		}
+		*c.dst = value
+	}
+
+	if certification := params.Get("certification"); certification != "" {
+		country, rating, ok := strings.Cut(certification, ":")
+		if !ok || rating == "" || !iso.IsCountry(strings.ToUpper(country)) {
+			return fmt.Errorf("%w: invalid certification %q, expected country and rating like US:PG-13", models.ErrBadRequest, certification)
+		}
+		query.CertificationCountry, query.Certification = country, rating
+	}
+
+	bounds := []struct {
+		name string
+		dst  *int
+	}{
+		{"runtime_min", &query.RuntimeMin},
+		{"runtime_max", &query.RuntimeMax},
+		{"budget_min", &query.BudgetMin},
+		{"budget_max", &query.BudgetMax},
+		{"box_office_min", &query.BoxOfficeMin},
+		{"box_office_max", &query.BoxOfficeMax},
+	}
+
+	var err error
+
+	for _, b := range bounds {
+		if *b.dst, err = parseIntParam(params, b.name); err != nil {
+			return err
+		}
+	}
+
+	moneyFiltered := query.BudgetMin != 0 || query.BudgetMax != 0 || query.BoxOfficeMin != 0 || query.BoxOfficeMax != 0
+	if moneyFiltered && query.Currency == "" {
+		return fmt.Errorf("%w: currency is required to filter by budget or box office", models.ErrBadRequest)
+	}
+
+	return nil
+}
+
+func parseIntParam(params url.Values, name string) (int, error) {
+	value := params.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: invalid %s, must be a non-negative integer", models.ErrBadRequest, name)
+	}
+
+	return n, nil
+}
+
+func parseDateParam(params url.Values, name string) (*time.Time, error) {
+	value := params.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: invalid %s, expected YYYY-MM-DD or RFC 3339 timestamp", models.ErrBadRequest, name)
+}
+
+// nextPageLink builds link to the next page keeping the rest of request query.
+// Requests paged by cursor continue with cursor, others with offset
+func nextPageLink(r *http.Request, limit, offset int, cursor string) string {
+	params := r.URL.Query()
+	params.Set("limit", strconv.Itoa(limit))
+
+	if params.Has("cursor") && cursor != "" {
+		params.Set("cursor", cursor)
+	} else {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+
+	return r.URL.Path + "?" + params.Encode()
 }
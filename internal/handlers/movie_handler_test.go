@@ -2,21 +2,380 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
 	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
 	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/problem"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/mux"
 )
 
+// authenticatedAs passes requests on with principal having role, as auth middleware does for valid tokens
+func authenticatedAs(role string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := &auth.Principal{Subject: "tester", Role: role}
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		})
+	}
+}
+
+// staticRoles resolves roles from map, unknown subjects are viewers
+type staticRoles map[string]string
+
+func (s staticRoles) ResolveRole(ctx context.Context, subject string) (string, error) {
+	if role, ok := s[subject]; ok {
+		return role, nil
+	}
+
+	return models.RoleViewer, nil
+}
+
+func signToken(t *testing.T, secret, subject string, ttl time.Duration) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": subject,
+		"exp": time.Now().Add(ttl).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+// memoryMovieRepo implements movie repository on maps, so handler tests run the real movie service.
+// Movies are listed by id whatever sort is asked
+type memoryMovieRepo struct {
+	mu        sync.Mutex
+	movies    map[int]*models.Movie
+	revisions map[int][]*models.Revision
+	nextID    int
+	revision  int
+}
+
+func newMemoryMovieRepo() *memoryMovieRepo {
+	return &memoryMovieRepo{movies: map[int]*models.Movie{}, revisions: map[int][]*models.Revision{}, nextID: 1}
+}
+
+// newMovieTestService creates movie service on repo, movies of tests have no genres to look up
+func newMovieTestService(repo *memoryMovieRepo) service.MovieService {
+	return service.NewMovieService(repo, nil, []byte("cursor-secret"))
+}
+
+// add stores movies as created ones
+func (r *memoryMovieRepo) add(movies ...*models.Movie) {
+	for _, m := range movies {
+		r.Create(context.Background(), m)
+	}
+}
+
+func (r *memoryMovieRepo) Create(ctx context.Context, m *models.Movie) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m.ID = r.nextID
+	r.nextID++
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	m.Version = 1
+
+	stored := *m
+	r.movies[m.ID] = &stored
+	r.addRevision(ctx, &stored, models.RevisionCreate)
+
+	return nil
+}
+
+func (r *memoryMovieRepo) GetByID(ctx context.Context, id int) (*models.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.movies[id]
+	if !ok || m.DeletedAt != nil {
+		return nil, fmt.Errorf("movie %d: %w", id, models.ErrNotFound)
+	}
+
+	found := *m
+	return &found, nil
+}
+
+func (r *memoryMovieRepo) Update(ctx context.Context, id int, update func(*models.Movie) error) (*models.Movie, error) {
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := update(current); err != nil {
+		return nil, err
+	}
+
+	current.UpdatedAt = time.Now()
+	current.Version++
+	r.store(ctx, current, models.RevisionUpdate)
+
+	updated := *current
+	return &updated, nil
+}
+
+func (r *memoryMovieRepo) Delete(ctx context.Context, id, version int) (*models.Movie, error) {
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 && version != current.Version {
+		return nil, models.ErrPreconditionFailed
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := *current
+	deletedAt := time.Now()
+	deleted.DeletedAt = &deletedAt
+	deleted.Version++
+	r.store(ctx, &deleted, models.RevisionDelete)
+
+	return current, nil
+}
+
+func (r *memoryMovieRepo) ListDeleted(ctx context.Context, limit, offset int) ([]*models.Movie, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := []*models.Movie{}
+	for _, m := range r.movies {
+		if m.DeletedAt != nil {
+			deleted = append(deleted, m)
+		}
+	}
+
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].ID > deleted[j].ID })
+
+	return page(deleted, limit, offset), len(deleted), nil
+}
+
+func (r *memoryMovieRepo) Restore(ctx context.Context, id int) (*models.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.movies[id]
+	if !ok || m.DeletedAt == nil {
+		return nil, fmt.Errorf("deleted movie %d: %w", id, models.ErrNotFound)
+	}
+
+	restored := *m
+	restored.DeletedAt = nil
+	restored.Version++
+	r.store(ctx, &restored, models.RevisionRestore)
+
+	return &restored, nil
+}
+
+func (r *memoryMovieRepo) Purge(ctx context.Context, id int) (*models.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.movies[id]
+	if !ok || m.DeletedAt == nil {
+		return nil, fmt.Errorf("deleted movie %d: %w", id, models.ErrNotFound)
+	}
+
+	delete(r.movies, id)
+	delete(r.revisions, id)
+
+	return m, nil
+}
+
+func (r *memoryMovieRepo) ListRevisions(ctx context.Context, movieID int) ([]*models.Revision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revisions := []*models.Revision{}
+	for _, revision := range r.revisions[movieID] {
+		copied := *revision
+		revisions = append(revisions, &copied)
+	}
+
+	return revisions, nil
+}
+
+func (r *memoryMovieRepo) GetRevision(ctx context.Context, movieID, revisionID int) (*models.Revision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, revision := range r.revisions[movieID] {
+		if revision.ID == revisionID {
+			copied := *revision
+			return &copied, nil
+		}
+	}
+
+	return nil, fmt.Errorf("revision %d: %w", revisionID, models.ErrNotFound)
+}
+
+func (r *memoryMovieRepo) GetRevisionAt(ctx context.Context, movieID int, asOf time.Time) (*models.Revision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found *models.Revision
+	for _, revision := range r.revisions[movieID] {
+		if !revision.CreatedAt.After(asOf) {
+			copied := *revision
+			found = &copied
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("movie %d as of %s: %w", movieID, asOf.Format(time.RFC3339), models.ErrNotFound)
+	}
+
+	return found, nil
+}
+
+func (r *memoryMovieRepo) List(ctx context.Context, q *models.MovieQuery) ([]*models.Movie, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	matched := []*models.Movie{}
+	for _, m := range r.movies {
+		if m.DeletedAt == nil && matchesQuery(m, q) {
+			matched = append(matched, m)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+	total := len(matched)
+
+	if q.After != nil {
+		matched = slices.DeleteFunc(matched, func(m *models.Movie) bool { return m.ID <= q.After.ID })
+	}
+
+	return page(matched, q.Limit, q.Offset), total, nil
+}
+
+func (r *memoryMovieRepo) Search(ctx context.Context, q *models.SearchQuery) ([]*models.SearchResult, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	needle := strings.ToLower(q.Query)
+	results := []*models.SearchResult{}
+	for _, m := range r.movies {
+		text := strings.ToLower(m.Title + " " + m.Director + " " + m.Description)
+		if m.DeletedAt == nil && strings.Contains(text, needle) {
+			results = append(results, &models.SearchResult{Movie: m, Rank: 1, Snippet: m.Description})
+		}
+	}
+
+	return results, len(results), nil
+}
+
+func (r *memoryMovieRepo) Suggest(ctx context.Context, text string, limit int) ([]*models.Suggestion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prefix := strings.ToLower(text)
+	suggestions := []*models.Suggestion{}
+	for _, m := range r.movies {
+		if len(suggestions) < limit && m.DeletedAt == nil && strings.HasPrefix(strings.ToLower(m.Title), prefix) {
+			suggestions = append(suggestions, &models.Suggestion{ID: m.ID, Title: m.Title, Director: m.Director, ReleaseDate: m.ReleaseDate, Score: 1})
+		}
+	}
+
+	return suggestions, nil
+}
+
+// store saves m and its revision, caller must hold the lock
+func (r *memoryMovieRepo) store(ctx context.Context, m *models.Movie, action string) {
+	stored := *m
+	r.movies[m.ID] = &stored
+	r.addRevision(ctx, &stored, action)
+}
+
+// addRevision records copy of the movie made by principal of ctx, caller must hold the lock
+func (r *memoryMovieRepo) addRevision(ctx context.Context, m *models.Movie, action string) {
+	snapshot := *m
+	changedBy := ""
+	if principal, ok := auth.FromContext(ctx); ok {
+		changedBy = principal.Subject
+	}
+
+	r.revision++
+	r.revisions[m.ID] = append(r.revisions[m.ID], &models.Revision{
+		ID:        r.revision,
+		MovieID:   m.ID,
+		Version:   m.Version,
+		Action:    action,
+		ChangedBy: changedBy,
+		CreatedAt: time.Now(),
+		Snapshot:  &snapshot,
+	})
+}
+
+// matchesQuery reports whether movie passes filters of q which tests use
+func matchesQuery(m *models.Movie, q *models.MovieQuery) bool {
+	matchedTags := 0
+	for _, tag := range q.Tags {
+		if slices.Contains(m.Tags, tag) {
+			matchedTags++
+		}
+	}
+
+	switch {
+	case q.Genre != "" && !slices.Contains(m.Genres, strings.ToLower(q.Genre)):
+		return false
+	case q.Director != "" && !strings.Contains(strings.ToLower(m.Director), strings.ToLower(q.Director)):
+		return false
+	case len(q.Tags) > 0 && (matchedTags == 0 || !q.AnyTag && matchedTags < len(q.Tags)):
+		return false
+	case q.Country != "" && !slices.Contains(m.Countries, q.Country):
+		return false
+	case q.Language != "" && !slices.Contains(m.Languages, q.Language):
+		return false
+	case q.RuntimeMin != 0 && (m.Runtime == nil || *m.Runtime < q.RuntimeMin):
+		return false
+	case q.RuntimeMax != 0 && (m.Runtime == nil || *m.Runtime > q.RuntimeMax):
+		return false
+	}
+
+	return true
+}
+
+// page returns copies of movies between offset and offset+limit
+func page(movies []*models.Movie, limit, offset int) []*models.Movie {
+	paged := []*models.Movie{}
+	for i := offset; i < len(movies) && i < offset+limit; i++ {
+		copied := *movies[i]
+		paged = append(paged, &copied)
+	}
+
+	return paged
+}
+
 func TestMovieHandler_CreateMovie_Succes(t *testing.T) {
 	mockService := NewMockMovieService()
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	movie := &models.Movie{
 		Title:       "Test Movie",
@@ -54,7 +413,7 @@ func TestMovieHandler_CreateMovie_Succes(t *testing.T) {
 func TestMovieHandler_CreateMovie_InvalidJSON(t *testing.T) {
 	mockService := NewMockMovieService()
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	body := []byte(`{"invalid json": "something"`)
 	req := httptest.NewRequest("POST", "/movies", bytes.NewReader(body))
@@ -72,7 +431,7 @@ func TestMovieHandler_CreateMovie_ServiceError(t *testing.T) {
 	mockService := NewMockMovieService().(*MockMovieService)
 	mockService.SetErrorMode("CreateMovie", true)
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	movie := &models.Movie{Title: "Test Movie"}
 
@@ -91,7 +450,7 @@ func TestMovieHandler_CreateMovie_ServiceError(t *testing.T) {
 func TestMovieHandler_GetMovie_Succes(t *testing.T) {
 	mockService := NewMockMovieService().(*MockMovieService)
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	testMovie := &models.Movie{
 		Title:       "Exsisting Movie",
@@ -131,7 +490,7 @@ func TestMovieHandler_GetMovie_NotFound(t *testing.T) {
 	mockService := NewMockMovieService().(*MockMovieService)
 	mockService.SetErrorMode("GetMovie", true)
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	req := httptest.NewRequest("GET", "/movies/1", nil)
 	w := httptest.NewRecorder()
@@ -145,10 +504,27 @@ func TestMovieHandler_GetMovie_NotFound(t *testing.T) {
 	}
 }
 
+func TestMovieHandler_GetMovie_Missing(t *testing.T) {
+	mockService := NewMockMovieService()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(mockService, logger, "local")
+
+	req := httptest.NewRequest("GET", "/movies/42", nil)
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/movies/{id}", handler.GetMovie).Methods("GET")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
+
 func TestMovieHandler_GetMovie_InvalidID(t *testing.T) {
 	mockService := NewMockMovieService()
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	req := httptest.NewRequest("GET", "/movies/invalid", nil)
 	w := httptest.NewRecorder()
@@ -162,10 +538,10 @@ func TestMovieHandler_GetMovie_InvalidID(t *testing.T) {
 	}
 }
 
-func TestMovieHandler_UpdateMovie_Partial(t *testing.T) {
-	mockService := NewMockMovieService().(*MockMovieService)
+func TestMovieHandler_UpdateMovie_ReplacesMovie(t *testing.T) {
+	repo := newMemoryMovieRepo()
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
 
 	initialMovie := &models.Movie{
 		Title:       "Original Title",
@@ -175,24 +551,89 @@ func TestMovieHandler_UpdateMovie_Partial(t *testing.T) {
 		Description: "Original Description",
 	}
 
-	mockService.AddTestMovies(initialMovie)
+	repo.add(initialMovie)
+
+	body := []byte(`{"title": "New Title", "director": "New Director", "release_date": "2010-07-16T00:00:00Z"}`)
+
+	req := httptest.NewRequest("PUT", "/movies/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r := mux.NewRouter()
+	r.Use(authenticatedAs(models.RoleEditor))
+	handler.RegisterRoutes(r)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	response := models.Movie{}
 
-	updateData := map[string]interface{}{
-		"Title": "Only new title",
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Title != "New Title" || response.Director != "New Director" {
+		t.Errorf("Expected title and director to be replaced, got %+v", response)
 	}
 
-	body, _ := json.Marshal(updateData)
+	if response.Genre != "" || response.Description != "" {
+		t.Errorf("Expected omitted fields to be cleared, got %+v", response)
+	}
+}
+
+func TestMovieHandler_UpdateMovie_MissingRequiredFields(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Original Title", Director: "Original Director", ReleaseDate: time.Now()})
+
+	body := []byte(`{"title": "Only new title"}`)
 
 	req := httptest.NewRequest("PUT", "/movies/1", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	r := mux.NewRouter()
-	r.HandleFunc("/movies/{id}", handler.UpdateMovie).Methods("PUT")
+	r.Use(authenticatedAs(models.RoleEditor))
+	handler.RegisterRoutes(r)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", w.Code)
+	}
+}
+
+func TestMovieHandler_PatchMovie_MergePatch(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	initialMovie := &models.Movie{
+		Title:       "Original Title",
+		Director:    "Original Director",
+		ReleaseDate: time.Now(),
+		Genre:       "Original Genre",
+		Description: "Original Description",
+	}
+
+	repo.add(initialMovie)
+
+	body := []byte(`{"title": "Only new title", "description": null}`)
+
+	req := httptest.NewRequest("PATCH", "/movies/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	w := httptest.NewRecorder()
+
+	r := mux.NewRouter()
+	r.Use(authenticatedAs(models.RoleEditor))
+	handler.RegisterRoutes(r)
 	r.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
+		t.Fatalf("Expected status 200, got %d", w.Code)
 	}
 
 	response := models.Movie{}
@@ -213,15 +654,79 @@ func TestMovieHandler_UpdateMovie_Partial(t *testing.T) {
 		t.Error("Expected genre would not be changed")
 	}
 
-	if response.Description != "Original Description" {
-		t.Error("Expected description would not be changed")
+	if response.Description != "" {
+		t.Errorf("Expected description to be cleared, got %s", response.Description)
+	}
+}
+
+func TestMovieHandler_PatchMovie_JSONPatch(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+
+	tests := []struct {
+		patch  string
+		status int
+	}{
+		{`[{"op": "test", "path": "/title", "value": "Heat"}, {"op": "replace", "path": "/title", "value": "Heat (1995)"}]`, http.StatusOK},
+		{`[{"op": "test", "path": "/title", "value": "Heat"}]`, http.StatusConflict},
+		{`[{"op": "remove", "path": "/awards"}]`, http.StatusUnprocessableEntity},
+		{`[{"op": "replace", "path": "/title", "value": ""}]`, http.StatusUnprocessableEntity},
+		{`{"op": "replace"}`, http.StatusBadRequest},
+	}
+
+	r := mux.NewRouter()
+	r.Use(authenticatedAs(models.RoleEditor))
+	handler.RegisterRoutes(r)
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("PATCH", "/movies/1", bytes.NewReader([]byte(tt.patch)))
+		req.Header.Set("Content-Type", "application/json-patch+json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("Patch %s: expected status %d, got %d", tt.patch, tt.status, w.Code)
+		}
+	}
+
+	movie, _ := repo.GetByID(context.Background(), 1)
+	if movie.Title != "Heat (1995)" {
+		t.Errorf("Expected only successful patch to be applied, got title %s", movie.Title)
+	}
+}
+
+func TestMovieHandler_PatchMovie_UnsupportedMediaType(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+
+	req := httptest.NewRequest("PATCH", "/movies/1", bytes.NewReader([]byte(`{"title": "Ronin"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r := mux.NewRouter()
+	r.Use(authenticatedAs(models.RoleEditor))
+	handler.RegisterRoutes(r)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected status 415, got %d", w.Code)
+	}
+
+	if w.Header().Get("Accept-Patch") == "" {
+		t.Error("Expected Accept-Patch header to list supported patch types")
 	}
 }
 
 func TestMovieHandler_DeleteMovie_Succes(t *testing.T) {
 	mockService := NewMockMovieService().(*MockMovieService)
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	initialMovie := &models.Movie{Title: "first movie"}
 
@@ -243,10 +748,27 @@ func TestMovieHandler_DeleteMovie_Succes(t *testing.T) {
 	}
 }
 
+func TestMovieHandler_DeleteMovie_NotFound(t *testing.T) {
+	mockService := NewMockMovieService()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(mockService, logger, "local")
+
+	req := httptest.NewRequest("DELETE", "/movies/42", nil)
+	w := httptest.NewRecorder()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/movies/{id}", handler.DeleteMovie).Methods("DELETE")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
+
 func TestMovieHandler_ListMovies_Succes(t *testing.T) {
 	mockService := NewMockMovieService().(*MockMovieService)
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	initialMovies := []*models.Movie{
 		{Title: "Title 1"},
@@ -263,33 +785,895 @@ func TestMovieHandler_ListMovies_Succes(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
 
-	var response []*models.Movie
+	var response models.MovieList
 
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatal(err)
 	}
 
-	if len(response) != 2 {
-		t.Errorf("Expected movies count to be 2, got %d", len(response))
+	if len(response.Movies) != 2 {
+		t.Errorf("Expected movies count to be 2, got %d", len(response.Movies))
+	}
+
+	if response.Total != 2 {
+		t.Errorf("Expected total to be 2, got %d", response.Total)
+	}
+
+	if response.Next != "" {
+		t.Errorf("Expected no next page link, got %s", response.Next)
 	}
 }
 
 func TestMovieHandler_ListMovies_Empty(t *testing.T) {
 	mockService := NewMockMovieService()
 	logger := logger.NewLogger("local")
-	handler := NewMovieHandler(mockService, logger)
+	handler := NewMovieHandler(mockService, logger, "local")
 
 	req := httptest.NewRequest("GET", "/movies", nil)
 	w := httptest.NewRecorder()
 	handler.ListMovies(w, req)
 
-	var response []*models.Movie
+	var response models.MovieList
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Movies) != 0 {
+		t.Errorf("Movie count must be zero, got %d", len(response.Movies))
+	}
+}
+
+func TestMovieHandler_ListMovies_Paginated(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(
+		&models.Movie{Title: "Title 1", Genres: []string{"drama"}},
+		&models.Movie{Title: "Title 2", Genres: []string{"comedy"}},
+		&models.Movie{Title: "Title 3", Genres: []string{"crime", "drama"}},
+		&models.Movie{Title: "Title 4", Genres: []string{"drama"}},
+	)
+
+	req := httptest.NewRequest("GET", "/movies?genre=drama&limit=2", nil)
+	w := httptest.NewRecorder()
+	handler.ListMovies(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	var response models.MovieList
 
 	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 		t.Fatal(err)
 	}
 
-	if len(response) != 0 {
-		t.Errorf("Movie count must be zero, got %d", len(response))
+	if len(response.Movies) != 2 {
+		t.Errorf("Expected movies count to be 2, got %d", len(response.Movies))
+	}
+
+	if response.Total != 3 {
+		t.Errorf("Expected total to be 3, got %d", response.Total)
+	}
+
+	if response.Next != "/movies?genre=drama&limit=2&offset=2" {
+		t.Errorf("Unexpected next page link %s", response.Next)
+	}
+}
+
+func TestMovieHandler_ListMovies_Tags(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(
+		&models.Movie{Title: "Title 1", Tags: []string{"heist"}},
+		&models.Movie{Title: "Title 2", Tags: []string{"heist", "time travel"}},
+		&models.Movie{Title: "Title 3", Tags: []string{"time travel"}},
+		&models.Movie{Title: "Title 4"},
+	)
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"tags=heist,Time%20Travel", 1},
+		{"tags=heist,time%20travel&tag_mode=all", 1},
+		{"tags=heist,time%20travel&tag_mode=any", 3},
+		{"tags=heist", 2},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/movies?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		handler.ListMovies(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %q, got %d", tt.query, w.Code)
+		}
+
+		var response models.MovieList
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+
+		if response.Total != tt.expected {
+			t.Errorf("Expected %d movies for %q, got %d", tt.expected, tt.query, response.Total)
+		}
+	}
+}
+
+func TestMovieHandler_ListMovies_Metadata(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	short, long := 95, 170
+
+	repo.add(
+		&models.Movie{Title: "Title 1", Countries: []string{"US"}, Languages: []string{"en"}, Runtime: &long},
+		&models.Movie{Title: "Title 2", Countries: []string{"FR", "US"}, Languages: []string{"fr"}, Runtime: &short},
+		&models.Movie{Title: "Title 3", Countries: []string{"FR"}},
+	)
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"country=us", 2},
+		{"country=FR&language=FR", 1},
+		{"runtime_min=100", 1},
+		{"runtime_max=120", 1},
+		{"country=DE", 0},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/movies?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		handler.ListMovies(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %q, got %d", tt.query, w.Code)
+		}
+
+		var response models.MovieList
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+
+		if response.Total != tt.expected {
+			t.Errorf("Expected %d movies for %q, got %d", tt.expected, tt.query, response.Total)
+		}
+	}
+}
+
+func TestMovieHandler_ListMovies_InvalidQuery(t *testing.T) {
+	mockService := NewMockMovieService()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(mockService, logger, "local")
+
+	for _, query := range []string{"limit=-1", "offset=abc", "sort=budget", "order=up", "released_from=yesterday", "tag_mode=some",
+		"country=XX", "language=english", "certification=PG-13", "budget_min=1000", "runtime_max=long",
+	} {
+		req := httptest.NewRequest("GET", "/movies?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ListMovies(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %q, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestMovieHandler_ListMovies_Cursor(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(
+		&models.Movie{Title: "Title 1"},
+		&models.Movie{Title: "Title 2"},
+		&models.Movie{Title: "Title 3"},
+	)
+
+	list := func(query string) models.MovieList {
+		req := httptest.NewRequest("GET", "/movies?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ListMovies(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %q, got %d", query, w.Code)
+		}
+
+		var response models.MovieList
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+
+		return response
+	}
+
+	first := list("limit=2")
+	if first.NextCursor == "" {
+		t.Fatal("Expected cursor of the next page")
+	}
+
+	response := list("limit=2&cursor=" + first.NextCursor)
+
+	if len(response.Movies) != 1 || response.Movies[0].ID != 3 {
+		t.Errorf("Expected movies after cursor, got %+v", response.Movies)
+	}
+
+	if response.Next != "" {
+		t.Errorf("Expected no next page link, got %s", response.Next)
+	}
+}
+
+func TestMovieHandler_ListMovies_InvalidCursor(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	req := httptest.NewRequest("GET", "/movies?cursor=garbage", nil)
+	w := httptest.NewRecorder()
+	handler.ListMovies(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestMovieHandler_SearchMovies_Succes(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(
+		&models.Movie{Title: "The Dark Knight", Director: "Christopher Nolan"},
+		&models.Movie{Title: "Heat", Director: "Michael Mann"},
+	)
+
+	req := httptest.NewRequest("GET", "/movies/search?q=nolan", nil)
+	w := httptest.NewRecorder()
+	handler.SearchMovies(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	var response models.SearchResults
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Total != 1 || response.Results[0].Movie.Title != "The Dark Knight" {
+		t.Errorf("Expected one matching movie, got %+v", response.Results)
+	}
+}
+
+func TestMovieHandler_SearchMovies_EmptyQuery(t *testing.T) {
+	mockService := NewMockMovieService()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(mockService, logger, "local")
+
+	req := httptest.NewRequest("GET", "/movies/search?q=++", nil)
+	w := httptest.NewRecorder()
+	handler.SearchMovies(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestMovieHandler_SuggestMovies_Succes(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(
+		&models.Movie{Title: "The Godfather"},
+		&models.Movie{Title: "The Shawshank Redemption"},
+		&models.Movie{Title: "Heat"},
+	)
+
+	req := httptest.NewRequest("GET", "/movies/suggest?q=the&limit=5", nil)
+	w := httptest.NewRecorder()
+	handler.SuggestMovies(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	var response []*models.Suggestion
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response) != 2 {
+		t.Errorf("Expected 2 suggestions, got %d", len(response))
+	}
+}
+
+func TestMovieHandler_CreateMovie_ValidationError(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	body := []byte(`{"title": "", "director": "Test Director", "release_date": "1700-01-01T00:00:00Z"}`)
+	req := httptest.NewRequest("POST", "/movies", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(auth.NewContext(req.Context(), &auth.Principal{Subject: "tester", Role: models.RoleEditor}))
+	w := httptest.NewRecorder()
+
+	handler.CreateMovie(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", w.Code)
+	}
+
+	var response problem.Problem
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Errorf("Expected content type %s, got %s", problem.ContentType, ct)
+	}
+
+	expected := []problem.InvalidParam{
+		{Name: "title", Code: service.CodeRequired},
+		{Name: "release_date", Code: service.CodeOutOfRange},
+	}
+
+	if len(response.InvalidParams) != len(expected) {
+		t.Fatalf("Expected %d invalid params, got %+v", len(expected), response.InvalidParams)
+	}
+
+	for i, param := range response.InvalidParams {
+		if param.Name != expected[i].Name || param.Code != expected[i].Code {
+			t.Errorf("Expected %s/%s, got %s/%s", expected[i].Name, expected[i].Code, param.Name, param.Code)
+		}
+	}
+}
+
+func TestMovieHandler_CreateMovie_UnknownFields(t *testing.T) {
+	mockService := NewMockMovieService().(*MockMovieService)
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(mockService, logger, "local")
+
+	body := []byte(`{"Title": "Test Movie", "awards": 5, "studio": "A24"}`)
+	req := httptest.NewRequest("POST", "/movies", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateMovie(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", w.Code)
+	}
+
+	var response problem.Problem
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	params := response.InvalidParams
+	if len(params) != 2 || params[0].Name != "awards" || params[1].Name != "studio" {
+		t.Errorf("Expected awards and studio to be reported, got %+v", params)
+	}
+
+	if mockService.GetMovieCount() != 0 {
+		t.Error("Expected movie not to be created")
+	}
+}
+
+func TestMovieHandler_GetMovie_ProblemDetails(t *testing.T) {
+	mockService := NewMockMovieService()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(mockService, logger, "local")
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewRequestIDMiddleware())
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest("GET", "/movies/42", nil)
+	req.Header.Set(middleware.RequestIDHeader, "test-request")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Errorf("Expected content type %s, got %s", problem.ContentType, ct)
+	}
+
+	var response problem.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Type != problem.TypeNotFound || response.Status != http.StatusNotFound {
+		t.Errorf("Expected not found problem, got %+v", response)
+	}
+
+	if response.Instance != "/movies/42" || response.RequestID != "test-request" {
+		t.Errorf("Expected instance and request id to be set, got %+v", response)
+	}
+}
+
+func TestMovieHandler_GetMovie_HidesInternalErrorInProd(t *testing.T) {
+	mockService := NewMockMovieService().(*MockMovieService)
+	mockService.SetErrorMode("GetMovie", true)
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(mockService, logger, "prod")
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest("GET", "/movies/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response problem.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Status != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", response.Status)
+	}
+
+	if response.Detail != "" {
+		t.Errorf("Expected internal error detail to be hidden, got %q", response.Detail)
+	}
+}
+
+func TestMovieHandler_GetMovie_ETag(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest("GET", "/movies/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	etag := w.Header().Get("ETag")
+	if etag != `"1"` {
+		t.Fatalf("Expected ETag \"1\", got %s", etag)
+	}
+
+	req = httptest.NewRequest("GET", "/movies/1", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304, got %d", w.Code)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Error("Expected 304 response without body")
+	}
+
+	// rating changes stats of the movie but not its version
+	repo.movies[1].Rating = models.RatingStats{Average: 7.5, Count: 2, Histogram: []int{0, 0, 0, 0, 0, 0, 1, 1, 0, 0}}
+
+	req = httptest.NewRequest("GET", "/movies/1", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 after rating, got %d", w.Code)
+	}
+
+	rated := w.Header().Get("ETag")
+	if !strings.HasPrefix(rated, `"1.`) {
+		t.Errorf("Expected ETag of version 1 with rating part, got %s", rated)
+	}
+
+	// re-rating 7 and 8 as 6 and 9 keeps number and sum of scores
+	repo.movies[1].Rating = models.RatingStats{Average: 7.5, Count: 2, Histogram: []int{0, 0, 0, 0, 0, 1, 0, 0, 1, 0}}
+
+	req = httptest.NewRequest("GET", "/movies/1", nil)
+	req.Header.Set("If-None-Match", rated)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 after re-rating, got %d", w.Code)
+	}
+}
+
+func TestMovieHandler_IfMatch(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+
+	router := mux.NewRouter()
+	router.Use(authenticatedAs(models.RoleAdmin))
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest("PATCH", "/movies/1", bytes.NewReader([]byte(`{"title": "Heat (1995)"}`)))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"1"`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	if etag := w.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("Expected ETag \"2\" after update, got %s", etag)
+	}
+
+	// second editor still holds the first version
+	body := []byte(`{"title": "Ronin", "director": "John Frankenheimer", "release_date": "1998-09-25T00:00:00Z"}`)
+	req = httptest.NewRequest("PUT", "/movies/1", bytes.NewReader(body))
+	req.Header.Set("If-Match", `"1"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status 412 for stale PUT, got %d", w.Code)
+	}
+
+	// without If-Match version in body is checked
+	body = []byte(`{"title": "Ronin", "director": "John Frankenheimer", "release_date": "1998-09-25T00:00:00Z", "version": 1}`)
+	req = httptest.NewRequest("PUT", "/movies/1", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status 412 for stale version in body, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("DELETE", "/movies/1", nil)
+	req.Header.Set("If-Match", `W/"2"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status 412 for weak If-Match, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("DELETE", "/movies/1", nil)
+	req.Header.Set("If-Match", `"2.9f0c41a2"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+}
+
+func TestMovieHandler_Trash(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+
+	router := mux.NewRouter()
+	router.Use(authenticatedAs(models.RoleAdmin))
+	handler.RegisterRoutes(router)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	if w := serve("DELETE", "/movies/1"); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+
+	if w := serve("GET", "/movies/1"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected deleted movie to be hidden, got status %d", w.Code)
+	}
+
+	anonymous := mux.NewRouter()
+	handler.RegisterRoutes(anonymous)
+
+	w := httptest.NewRecorder()
+	anonymous.ServeHTTP(w, httptest.NewRequest("GET", "/movies/1/history", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected history of deleted movie to be hidden from anonymous, got status %d", w.Code)
+	}
+
+	if w := serve("GET", "/movies/1/history"); w.Code != http.StatusOK {
+		t.Errorf("Expected history of deleted movie to be shown to admin, got status %d", w.Code)
+	}
+
+	w = serve("GET", "/movies/trash")
+
+	var trash models.MovieList
+	if err := json.Unmarshal(w.Body.Bytes(), &trash); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trash.Movies) != 1 || trash.Movies[0].DeletedAt == nil {
+		t.Fatalf("Expected deleted movie in trash, got %+v", trash.Movies)
+	}
+
+	if w := serve("POST", "/movies/1/restore"); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	if w := serve("GET", "/movies/1"); w.Code != http.StatusOK {
+		t.Errorf("Expected restored movie to be visible, got status %d", w.Code)
+	}
+
+	if w := serve("POST", "/movies/1/restore"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for movie which is not in trash, got %d", w.Code)
+	}
+}
+
+func TestMovieHandler_PurgeMovie(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+	repo.Delete(context.Background(), 1, 0)
+
+	verifier, err := auth.NewVerifier(&config.Config{JWTSecret: "jwt-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{"root": models.RoleAdmin, "bob": models.RoleEditor}, logger))
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		subject string
+		status  int
+	}{
+		{"", http.StatusUnauthorized},
+		{"alice", http.StatusForbidden},
+		{"bob", http.StatusForbidden},
+		{"root", http.StatusNoContent},
+		{"root", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("DELETE", "/admin/movies/1", nil)
+		if tt.subject != "" {
+			req.Header.Set("Authorization", "Bearer "+signToken(t, "jwt-secret", tt.subject, time.Hour))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("Subject %q: expected status %d, got %d", tt.subject, tt.status, w.Code)
+		}
+
+		if w.Code == http.StatusForbidden && w.Header().Get("Content-Type") != problem.ContentType {
+			t.Errorf("Subject %q: expected problem details, got %q", tt.subject, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestMovieHandler_History(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{
+		Title:       "Heat",
+		Director:    "Michael Mann",
+		ReleaseDate: time.Now(),
+		Description: "Original Description",
+	})
+
+	router := mux.NewRouter()
+	router.Use(authenticatedAs(models.RoleEditor))
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest("PATCH", "/movies/1", bytes.NewReader([]byte(`{"description": "Vandalized"}`)))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// tag endpoints change tags through movie update
+	repo.Update(context.Background(), 1, func(m *models.Movie) error {
+		m.Tags = []string{"heist"}
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/movies/1/history", nil))
+
+	var history []models.Revision
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 3 || history[1].Action != models.RevisionUpdate {
+		t.Fatalf("Expected two updates and create revision, got %+v", history)
+	}
+
+	if history[1].ChangedBy != "tester" {
+		t.Errorf("Expected update to be made by tester, got %q", history[1].ChangedBy)
+	}
+
+	changes := history[1].Changes
+	if len(changes) != 1 || changes[0].Field != "description" || changes[0].Old != "Original Description" || changes[0].New != "Vandalized" {
+		t.Errorf("Expected description change, got %+v", changes)
+	}
+
+	if changes := history[0].Changes; len(changes) != 1 || changes[0].Field != "tags" {
+		t.Errorf("Expected tags change, got %+v", changes)
+	}
+
+	req = httptest.NewRequest("POST", "/movies/1/history/1/revert", nil)
+	req.Header.Set("If-Match", `"3"`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var reverted models.Movie
+	if err := json.Unmarshal(w.Body.Bytes(), &reverted); err != nil {
+		t.Fatal(err)
+	}
+
+	if reverted.Description != "Original Description" || len(reverted.Tags) != 0 || reverted.Version != 4 {
+		t.Errorf("Expected original description without tags at version 4, got %+v", reverted)
+	}
+}
+
+func TestMovieHandler_GetMovie_AsOf(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	tests := map[string]int{
+		"/movies/1?as_of=2000-01-01":                                        http.StatusNotFound,
+		"/movies/1?as_of=" + time.Now().Add(time.Hour).Format(time.RFC3339): http.StatusOK,
+		"/movies/1?as_of=yesterday":                                         http.StatusBadRequest,
+	}
+
+	for path, status := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		if w.Code != status {
+			t.Errorf("GET %s: expected status %d, got %d", path, status, w.Code)
+		}
+	}
+}
+
+type auditSink struct {
+	entries []*models.AuditEntry
+}
+
+func (s *auditSink) Create(ctx context.Context, entry *models.AuditEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func TestMovieHandler_AuditLog(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+	repo.Delete(context.Background(), 1, 0)
+
+	sink := &auditSink{}
+
+	verifier, err := auth.NewVerifier(&config.Config{JWTSecret: "jwt-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewRequestIDMiddleware())
+	router.Use(middleware.NewAuditMiddleware(sink, logger))
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{"root": models.RoleAdmin, "alice": models.RoleEditor}, logger))
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest("GET", "/movies/trash", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest("POST", "/movies/1/restore", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	req.Header.Set("Authorization", "Bearer "+signToken(t, "jwt-secret", "alice", time.Hour))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	repo.Delete(context.Background(), 1, 0)
+
+	req = httptest.NewRequest("DELETE", "/admin/movies/1", nil)
+	req.Header.Set("Authorization", "Bearer "+signToken(t, "jwt-secret", "root", time.Hour))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(sink.entries) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(sink.entries))
+	}
+
+	restore := sink.entries[0]
+	if restore.Actor != "alice" || restore.Method != "POST" || restore.Path != "/movies/1/restore" {
+		t.Errorf("Unexpected restore entry: %+v", restore)
+	}
+	if restore.Status != http.StatusOK || restore.RequestID != "req-1" {
+		t.Errorf("Expected status 200 and request id req-1, got %d and %q", restore.Status, restore.RequestID)
+	}
+
+	purge := sink.entries[1]
+	if purge.Actor != "root" || purge.Method != "DELETE" || purge.Status != http.StatusNoContent {
+		t.Errorf("Unexpected purge entry: %+v", purge)
+	}
+}
+
+func TestMovieHandler_WritesRequireAuth(t *testing.T) {
+	repo := newMemoryMovieRepo()
+	logger := logger.NewLogger("local")
+	handler := NewMovieHandler(newMovieTestService(repo), logger, "local")
+
+	repo.add(&models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: time.Now()})
+
+	verifier, err := auth.NewVerifier(&config.Config{JWTSecret: "jwt-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{"alice": models.RoleAdmin}, logger))
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		method string
+		token  string
+		status int
+	}{
+		{"GET", "", http.StatusOK},
+		{"GET", "garbage", http.StatusOK},
+		{"DELETE", "", http.StatusUnauthorized},
+		{"DELETE", "garbage", http.StatusUnauthorized},
+		{"DELETE", signToken(t, "jwt-secret", "alice", -time.Minute), http.StatusUnauthorized},
+		{"DELETE", signToken(t, "other-secret", "alice", time.Hour), http.StatusUnauthorized},
+		{"DELETE", signToken(t, "jwt-secret", "alice", time.Hour), http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/movies/1", nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s with token %q: expected status %d, got %d", tt.method, tt.token, tt.status, w.Code)
+		}
+
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s with token %q: expected WWW-Authenticate header", tt.method, tt.token)
+		}
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/movies/trash", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous trash listing to be rejected, got %d", w.Code)
 	}
 }
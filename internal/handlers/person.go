@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type PersonHandler struct {
+	service service.PersonService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewPersonHandler(service service.PersonService, log *slog.Logger, env string) *PersonHandler {
+	return &PersonHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers people and credit routes, reads are public and writes require authenticated caller
+func (h *PersonHandler) RegisterRoutes(router *mux.Router) {
+	writes := router.NewRoute().Subrouter()
+	writes.Use(middleware.RequireAuth)
+	writes.HandleFunc("/people", h.CreatePerson).Methods("POST")
+	writes.HandleFunc("/people/{id}", h.UpdatePerson).Methods("PUT")
+	writes.HandleFunc("/people/{id}", h.DeletePerson).Methods("DELETE")
+	writes.HandleFunc("/movies/{id}/credits", h.AddCredit).Methods("POST")
+	writes.HandleFunc("/movies/{id}/credits/{creditId}", h.DeleteCredit).Methods("DELETE")
+
+	router.HandleFunc("/people", h.ListPeople).Methods("GET")
+	router.HandleFunc("/people/{id}", h.GetPerson).Methods("GET")
+	router.HandleFunc("/people/{id}/movies", h.GetFilmography).Methods("GET")
+	router.HandleFunc("/movies/{id}/credits", h.ListMovieCredits).Methods("GET")
+}
+
+func (h *PersonHandler) CreatePerson(w http.ResponseWriter, r *http.Request) {
+	var person models.Person
+
+	if err := decodeJSON(r, &person); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode person body", "error", err)
+		return
+	}
+
+	if err := h.service.CreatePerson(r.Context(), &person); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to create person", "error", err)
+		return
+	}
+
+	h.log.Info("Person created succesfully", "ID", person.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(person)
+}
+
+func (h *PersonHandler) GetPerson(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid person id", models.ErrBadRequest))
+		h.log.Error("Invalid person id", "error", err)
+		return
+	}
+
+	person, err := h.service.GetPerson(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get person", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(person)
+}
+
+func (h *PersonHandler) UpdatePerson(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid person id", models.ErrBadRequest))
+		h.log.Error("Invalid person id", "error", err)
+		return
+	}
+
+	var person models.Person
+	if err := decodeJSON(r, &person); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode person body", "error", err)
+		return
+	}
+	person.ID = id
+
+	updated, err := h.service.UpdatePerson(r.Context(), &person)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to update person", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Person updated succesfully", "ID", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *PersonHandler) DeletePerson(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid person id", models.ErrBadRequest))
+		h.log.Error("Invalid person id", "error", err)
+		return
+	}
+
+	if err := h.service.DeletePerson(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete person", "error", err)
+		return
+	}
+
+	h.log.Info("Person was deleted succesfully", "ID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PersonHandler) ListPeople(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := &models.PersonQuery{Name: params.Get("name")}
+
+	var err error
+
+	if query.Limit, err = parseIntParam(params, "limit"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid people query", "error", err)
+		return
+	}
+
+	if query.Offset, err = parseIntParam(params, "offset"); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid people query", "error", err)
+		return
+	}
+
+	list, err := h.service.ListPeople(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list people", "error", err)
+		return
+	}
+
+	if list.Offset+len(list.People) < list.Total {
+		list.Next = nextPageLink(r, list.Limit, list.Offset+list.Limit, "")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *PersonHandler) GetFilmography(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid person id", models.ErrBadRequest))
+		h.log.Error("Invalid person id", "error", err)
+		return
+	}
+
+	credits, err := h.service.GetFilmography(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get filmography", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(credits)
+}
+
+func (h *PersonHandler) ListMovieCredits(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	credits, err := h.service.ListMovieCredits(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list movie credits", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(credits)
+}
+
+func (h *PersonHandler) AddCredit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	var credit models.Credit
+	if err := decodeJSON(r, &credit); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode credit body", "error", err)
+		return
+	}
+	credit.MovieID = id
+
+	if err := h.service.AddCredit(r.Context(), &credit); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to add credit", "error", err)
+		return
+	}
+
+	h.log.Info("Credit added succesfully", "ID", credit.ID, "movie", id)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(credit)
+}
+
+func (h *PersonHandler) DeleteCredit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	movieID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	creditID, err := strconv.Atoi(vars["creditId"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid credit id", models.ErrBadRequest))
+		h.log.Error("Invalid credit id", "error", err)
+		return
+	}
+
+	if err := h.service.DeleteCredit(r.Context(), movieID, creditID); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete credit", "error", err)
+		return
+	}
+
+	h.log.Info("Credit was deleted succesfully", "ID", creditID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/gorilla/mux"
+)
+
+func TestPersonHandler_CreatePerson_Succes(t *testing.T) {
+	mockService := NewMockPersonService()
+	logger := logger.NewLogger("local")
+	handler := NewPersonHandler(mockService, logger, "local")
+
+	body, _ := json.Marshal(&models.Person{Name: "Christopher Nolan"})
+	req := httptest.NewRequest("POST", "/people", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.CreatePerson(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", w.Code)
+	}
+
+	var response models.Person
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.ID == 0 || response.Name != "Christopher Nolan" {
+		t.Errorf("Unexpected person %+v", response)
+	}
+}
+
+func TestPersonHandler_AddCredit_Filmography(t *testing.T) {
+	mockService := NewMockPersonService()
+	logger := logger.NewLogger("local")
+	handler := NewPersonHandler(mockService, logger, "local")
+
+	person := &models.Person{Name: "Christopher Nolan"}
+	mockService.CreatePerson(t.Context(), person)
+
+	router := mux.NewRouter()
+	router.Use(authenticatedAs(models.RoleEditor))
+	handler.RegisterRoutes(router)
+
+	body, _ := json.Marshal(&models.Credit{PersonID: person.ID, Role: models.CreditDirector})
+	req := httptest.NewRequest("POST", "/movies/7/credits", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/people/1/movies", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var credits []*models.Credit
+	if err := json.Unmarshal(w.Body.Bytes(), &credits); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(credits) != 1 || credits[0].MovieID != 7 {
+		t.Errorf("Expected one credit for movie 7, got %+v", credits)
+	}
+}
+
+func TestPersonHandler_AddCredit_InvalidRole(t *testing.T) {
+	mockService := NewMockPersonService()
+	logger := logger.NewLogger("local")
+	handler := NewPersonHandler(mockService, logger, "local")
+
+	router := mux.NewRouter()
+	router.Use(authenticatedAs(models.RoleEditor))
+	handler.RegisterRoutes(router)
+
+	body, _ := json.Marshal(&models.Credit{PersonID: 1, Role: "producer"})
+	req := httptest.NewRequest("POST", "/movies/7/credits", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", w.Code)
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type RatingHandler struct {
+	service service.RatingService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewRatingHandler(service service.RatingService, log *slog.Logger, env string) *RatingHandler {
+	return &RatingHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers rating routes of the current user, service lets through only local users
+func (h *RatingHandler) RegisterRoutes(router *mux.Router) {
+	users := router.NewRoute().Subrouter()
+	users.Use(middleware.RequireAuth)
+	users.HandleFunc("/movies/{id}/rating", h.RateMovie).Methods("PUT")
+	users.HandleFunc("/movies/{id}/rating", h.GetRating).Methods("GET")
+	users.HandleFunc("/movies/{id}/rating", h.DeleteRating).Methods("DELETE")
+}
+
+func (h *RatingHandler) RateMovie(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	var rating models.Rating
+	if err := decodeJSON(r, &rating); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode rating body", "error", err)
+		return
+	}
+
+	result, err := h.service.RateMovie(r.Context(), id, rating.Score)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to rate movie", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Movie rated succesfully", "ID", id, "score", rating.Score)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *RatingHandler) GetRating(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	rating, err := h.service.GetRating(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get rating", "ID", id, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rating)
+}
+
+func (h *RatingHandler) DeleteRating(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	if err := h.service.DeleteRating(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete rating", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Rating was deleted succesfully", "ID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// memoryRatingRepo keeps ratings of movie 1 only, other movies don't exist
+type memoryRatingRepo struct {
+	ratings map[int]*models.Rating
+}
+
+func (r *memoryRatingRepo) Set(ctx context.Context, rating *models.Rating) error {
+	if rating.MovieID != 1 {
+		return models.ErrNotFound
+	}
+
+	rating.CreatedAt = time.Now()
+	rating.UpdatedAt = rating.CreatedAt
+	r.ratings[rating.UserID] = rating
+	return nil
+}
+
+func (r *memoryRatingRepo) Get(ctx context.Context, userID, movieID int) (*models.Rating, error) {
+	rating, ok := r.ratings[userID]
+	if !ok || rating.MovieID != movieID {
+		return nil, models.ErrNotFound
+	}
+
+	return rating, nil
+}
+
+func (r *memoryRatingRepo) Delete(ctx context.Context, userID, movieID int) error {
+	if _, err := r.Get(ctx, userID, movieID); err != nil {
+		return err
+	}
+
+	delete(r.ratings, userID)
+	return nil
+}
+
+func (r *memoryRatingRepo) Stats(ctx context.Context, movieID int) (*models.RatingStats, error) {
+	stats := &models.RatingStats{Histogram: make([]int, models.MaxRatingScore)}
+
+	sum := 0
+	for _, rating := range r.ratings {
+		stats.Count++
+		stats.Histogram[rating.Score-1]++
+		sum += rating.Score
+	}
+
+	if stats.Count > 0 {
+		stats.Average = float64(sum) / float64(stats.Count)
+	}
+
+	return stats, nil
+}
+
+func TestRatingHandler_RateMovie(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "jwt-secret"}
+	logger := logger.NewLogger("local")
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{}, logger))
+	ratingService := service.NewRatingService(&memoryRatingRepo{ratings: map[int]*models.Rating{}})
+	NewRatingHandler(ratingService, logger, "local").RegisterRoutes(router)
+
+	issuer := auth.NewIssuer(cfg, time.Minute)
+	tokenOf := func(userID int) string {
+		token, err := issuer.IssueUserToken(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	if w := serveJSON(router, "PUT", "/movies/1/rating", `{"score": 8}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous rating to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/movies/1/rating", `{"score": 8}`, signToken(t, "jwt-secret", "alice", time.Minute)); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected rating without local user to be rejected, got %d", w.Code)
+	}
+
+	for _, score := range []string{"0", "11"} {
+		if w := serveJSON(router, "PUT", "/movies/1/rating", `{"score": `+score+`}`, tokenOf(1)); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected score %s to be rejected, got %d", score, w.Code)
+		}
+	}
+
+	if w := serveJSON(router, "PUT", "/movies/2/rating", `{"score": 8}`, tokenOf(1)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for missing movie, got %d", w.Code)
+	}
+
+	serveJSON(router, "PUT", "/movies/1/rating", `{"score": 6}`, tokenOf(1))
+	serveJSON(router, "PUT", "/movies/1/rating", `{"score": 10}`, tokenOf(2))
+
+	w := serveJSON(router, "PUT", "/movies/1/rating", `{"score": 9}`, tokenOf(1))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var result models.RatingResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Rating.Score != 9 || result.Stats.Count != 2 || result.Stats.Average != 9.5 {
+		t.Errorf("Expected second score of user to replace the first one, got %+v %+v", result.Rating, result.Stats)
+	}
+
+	if result.Stats.Histogram[8] != 1 || result.Stats.Histogram[9] != 1 || result.Stats.Histogram[5] != 0 {
+		t.Errorf("Unexpected histogram %v", result.Stats.Histogram)
+	}
+
+	if w := serveJSON(router, "GET", "/movies/1/rating", "", tokenOf(2)); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "DELETE", "/movies/1/rating", "", tokenOf(2)); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "GET", "/movies/1/rating", "", tokenOf(2)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected deleted rating to be gone, got %d", w.Code)
+	}
+}
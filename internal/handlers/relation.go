@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type RelationHandler struct {
+	service service.RelationService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewRelationHandler(service service.RelationService, log *slog.Logger, env string) *RelationHandler {
+	return &RelationHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers routes of relations between movies, related movies are public
+// and changes of relations require authenticated caller
+func (h *RelationHandler) RegisterRoutes(router *mux.Router) {
+	writes := router.NewRoute().Subrouter()
+	writes.Use(middleware.RequireAuth)
+	writes.HandleFunc("/movies/{id}/relations", h.CreateRelation).Methods("POST")
+	writes.HandleFunc("/movies/{id}/relations/{relatedId}", h.DeleteRelation).Methods("DELETE")
+
+	router.HandleFunc("/movies/{id}/related", h.GetRelatedMovies).Methods("GET")
+}
+
+func (h *RelationHandler) CreateRelation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	var relation models.Relation
+	if err := decodeJSON(r, &relation); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode relation body", "error", err)
+		return
+	}
+	relation.MovieID = id
+
+	if err := h.service.CreateRelation(r.Context(), &relation); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to create relation", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Relation created succesfully", "ID", id, "related", relation.RelatedID, "type", relation.Type)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(relation)
+}
+
+func (h *RelationHandler) DeleteRelation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	relatedID, err := strconv.Atoi(vars["relatedId"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid related movie id", models.ErrBadRequest))
+		h.log.Error("Invalid related movie id", "error", err)
+		return
+	}
+
+	if err := h.service.DeleteRelation(r.Context(), id, relatedID); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete relation", "ID", id, "related", relatedID, "error", err)
+		return
+	}
+
+	h.log.Info("Relation was deleted succesfully", "ID", id, "related", relatedID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RelationHandler) GetRelatedMovies(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	related, err := h.service.GetRelatedMovies(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get related movies", "ID", id, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(related)
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// memoryRelationRepo keeps relations in a slice, movies 1 to 3 exist. It rejects repeated pairs only,
+// cycles are checked by query of postgres repository
+type memoryRelationRepo struct {
+	relations []*models.Relation
+}
+
+func (r *memoryRelationRepo) Create(ctx context.Context, rel *models.Relation) error {
+	if rel.MovieID > 3 || rel.RelatedID > 3 {
+		return models.ErrNotFound
+	}
+
+	for _, existing := range r.relations {
+		if existing.MovieID == rel.MovieID && existing.RelatedID == rel.RelatedID {
+			return models.ErrConflict
+		}
+	}
+
+	r.relations = append(r.relations, rel)
+	return nil
+}
+
+func (r *memoryRelationRepo) Delete(ctx context.Context, movieID, relatedID int) error {
+	return nil
+}
+
+func (r *memoryRelationRepo) ListRelated(ctx context.Context, movieID int) ([]*models.RelatedMovie, error) {
+	related := []*models.RelatedMovie{}
+	for _, rel := range r.relations {
+		switch movieID {
+		case rel.MovieID:
+			related = append(related, &models.RelatedMovie{MovieID: rel.RelatedID, Type: rel.Type})
+		case rel.RelatedID:
+			related = append(related, &models.RelatedMovie{MovieID: rel.MovieID, Type: rel.Type, Inverse: true})
+		}
+	}
+
+	return related, nil
+}
+
+func TestRelationHandler_Relations(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "jwt-secret"}
+	logger := logger.NewLogger("local")
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	franchises := &memoryFranchiseRepo{
+		franchises: []*models.Franchise{{ID: 1, Slug: "alien", Name: "Alien"}},
+		members:    map[int][]int{1: {1, 2}},
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{"editor": models.RoleEditor}, logger))
+	relationService := service.NewRelationService(&memoryRelationRepo{}, franchises)
+	NewRelationHandler(relationService, logger, "local").RegisterRoutes(router)
+
+	editor := signToken(t, "jwt-secret", "editor", time.Minute)
+	viewer := signToken(t, "jwt-secret", "viewer", time.Minute)
+
+	if w := serveJSON(router, "POST", "/movies/2/relations", `{"related_id": 1, "type": "sequel"}`, viewer); w.Code != http.StatusForbidden {
+		t.Errorf("Expected viewer to be forbidden, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/movies/2/relations", `{"related_id": 1, "type": "reboot"}`, editor); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected unknown type to be rejected, got %d", w.Code)
+	}
+
+	w := serveJSON(router, "POST", "/movies/2/relations", `{"related_id": 2, "type": "sequel"}`, editor)
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"name":"related_id"`) {
+		t.Errorf("Expected self-reference to be rejected on related_id, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveJSON(router, "POST", "/movies/2/relations", `{"related_id": 1, "type": "sequel"}`, editor); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveJSON(router, "POST", "/movies/3/relations", `{"related_id": 2, "type": "sequel"}`, editor); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveJSON(router, "POST", "/movies/3/relations", `{"related_id": 2, "type": "spin_off"}`, editor); w.Code != http.StatusConflict {
+		t.Errorf("Expected related movies to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/movies/1/relations", `{"related_id": 3, "type": "remake"}`, editor); w.Code != http.StatusCreated {
+		t.Errorf("Expected remake to be allowed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = serveJSON(router, "GET", "/movies/2/related", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var related models.RelatedMovies
+	if err := json.NewDecoder(w.Body).Decode(&related); err != nil {
+		t.Fatal(err)
+	}
+
+	sequelOf, sequels := related.Relations["sequel_of"], related.Relations["sequels"]
+	if len(sequelOf) != 1 || sequelOf[0].MovieID != 1 || len(sequels) != 1 || sequels[0].MovieID != 3 {
+		t.Errorf("Expected movie 2 to be sequel of 1 and have sequel 3, got %+v", related.Relations)
+	}
+
+	if len(related.Relations) != 2 {
+		t.Errorf("Expected only non-empty groups, got %+v", related.Relations)
+	}
+
+	if len(related.Franchises) != 1 || related.Franchises[0].Slug != "alien" {
+		t.Errorf("Expected movie 2 to be part of alien franchise, got %+v", related.Franchises)
+	}
+}
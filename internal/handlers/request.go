@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+)
+
+// decodeJSON decodes request body into dst and rejects fields which dst doesn't have.
+// Unknown fields are reported all at once as *service.ValidationError,
+// keys are matched case-insensitively like encoding/json does
+func decodeJSON(r *http.Request, dst any) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read body: %v", models.ErrBadRequest, err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrBadRequest, err)
+	}
+
+	known := jsonFields(reflect.TypeOf(dst).Elem())
+
+	var unknown []string
+	for key := range raw {
+		if !known[strings.ToLower(key)] {
+			unknown = append(unknown, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+
+		v := &service.ValidationError{}
+		for _, key := range unknown {
+			v.Add(key, service.CodeUnknownField, "unknown field")
+		}
+
+		return v
+	}
+
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(dst); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrBadRequest, err)
+	}
+
+	return nil
+}
+
+// jsonFields returns lowercased json names of struct fields
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+
+		fields[strings.ToLower(name)] = true
+	}
+
+	return fields
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type ReviewHandler struct {
+	service service.ReviewService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewReviewHandler(service service.ReviewService, log *slog.Logger, env string) *ReviewHandler {
+	return &ReviewHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers review, vote and moderation routes, approved reviews are public
+// and everything else requires authenticated caller
+func (h *ReviewHandler) RegisterRoutes(router *mux.Router) {
+	users := router.NewRoute().Subrouter()
+	users.Use(middleware.RequireAuth)
+	users.HandleFunc("/movies/{id}/reviews", h.CreateReview).Methods("POST")
+	users.HandleFunc("/reviews/{id}", h.UpdateReview).Methods("PUT")
+	users.HandleFunc("/reviews/{id}", h.DeleteReview).Methods("DELETE")
+	users.HandleFunc("/reviews/{id}/vote", h.VoteReview).Methods("PUT")
+	users.HandleFunc("/reviews/{id}/vote", h.DeleteReviewVote).Methods("DELETE")
+	users.HandleFunc("/moderation/reviews", h.ListModerationQueue).Methods("GET")
+	users.HandleFunc("/moderation/reviews/{id}", h.ModerateReview).Methods("PUT")
+
+	router.HandleFunc("/movies/{id}/reviews", h.ListMovieReviews).Methods("GET")
+	router.HandleFunc("/reviews/{id}", h.GetReview).Methods("GET")
+}
+
+func (h *ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	movieID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	var review models.Review
+	if err := decodeJSON(r, &review); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode review body", "error", err)
+		return
+	}
+	review.MovieID = movieID
+
+	if err := h.service.CreateReview(r.Context(), &review); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to create review", "movie", movieID, "error", err)
+		return
+	}
+
+	h.log.Info("Review created succesfully", "ID", review.ID, "movie", movieID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(review)
+}
+
+func (h *ReviewHandler) GetReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid review id", models.ErrBadRequest))
+		h.log.Error("Invalid review id", "error", err)
+		return
+	}
+
+	review, err := h.service.GetReview(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get review", "ID", id, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+func (h *ReviewHandler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid review id", models.ErrBadRequest))
+		h.log.Error("Invalid review id", "error", err)
+		return
+	}
+
+	var review models.Review
+	if err := decodeJSON(r, &review); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode review body", "error", err)
+		return
+	}
+	review.ID = id
+
+	updated, err := h.service.UpdateReview(r.Context(), &review)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to update review", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Review updated succesfully", "ID", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid review id", models.ErrBadRequest))
+		h.log.Error("Invalid review id", "error", err)
+		return
+	}
+
+	if err := h.service.DeleteReview(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete review", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Review was deleted succesfully", "ID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ReviewHandler) ListMovieReviews(w http.ResponseWriter, r *http.Request) {
+	movieID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	query, err := parseReviewQuery(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid reviews query", "error", err)
+		return
+	}
+	query.MovieID = movieID
+
+	list, err := h.service.ListMovieReviews(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list reviews", "movie", movieID, "error", err)
+		return
+	}
+
+	h.writeReviewList(w, r, list)
+}
+
+func (h *ReviewHandler) ListModerationQueue(w http.ResponseWriter, r *http.Request) {
+	query, err := parseReviewQuery(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid reviews query", "error", err)
+		return
+	}
+	query.Status = r.URL.Query().Get("status")
+
+	list, err := h.service.ListModerationQueue(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list moderation queue", "error", err)
+		return
+	}
+
+	h.writeReviewList(w, r, list)
+}
+
+func (h *ReviewHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid review id", models.ErrBadRequest))
+		h.log.Error("Invalid review id", "error", err)
+		return
+	}
+
+	var moderation models.Moderation
+	if err := decodeJSON(r, &moderation); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode moderation body", "error", err)
+		return
+	}
+
+	review, err := h.service.ModerateReview(r.Context(), id, &moderation)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to moderate review", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Review moderated succesfully", "ID", id, "status", review.Status)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+func (h *ReviewHandler) VoteReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid review id", models.ErrBadRequest))
+		h.log.Error("Invalid review id", "error", err)
+		return
+	}
+
+	var vote models.ReviewVote
+	if err := decodeJSON(r, &vote); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode vote body", "error", err)
+		return
+	}
+
+	if err := h.service.VoteReview(r.Context(), id, &vote); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to vote for review", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Review voted succesfully", "ID", id, "helpful", vote.Helpful)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ReviewHandler) DeleteReviewVote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid review id", models.ErrBadRequest))
+		h.log.Error("Invalid review id", "error", err)
+		return
+	}
+
+	if err := h.service.DeleteReviewVote(r.Context(), id); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete review vote", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Review vote was deleted succesfully", "ID", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ReviewHandler) writeReviewList(w http.ResponseWriter, r *http.Request, list *models.ReviewList) {
+	if list.Offset+len(list.Reviews) < list.Total {
+		list.Next = nextPageLink(r, list.Limit, list.Offset+list.Limit, "")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+var reviewSortFields = map[string]bool{
+	models.ReviewSortByCreatedAt: true,
+	models.ReviewSortByHelpful:   true,
+}
+
+// parseReviewQuery reads pagination and sorting of reviews, newest reviews go first by default
+func parseReviewQuery(r *http.Request) (*models.ReviewQuery, error) {
+	params := r.URL.Query()
+	query := &models.ReviewQuery{SortBy: models.ReviewSortByCreatedAt}
+
+	var err error
+
+	if query.Limit, err = parseIntParam(params, "limit"); err != nil {
+		return nil, err
+	}
+
+	if query.Offset, err = parseIntParam(params, "offset"); err != nil {
+		return nil, err
+	}
+
+	if sort := params.Get("sort"); sort != "" {
+		if !reviewSortFields[sort] {
+			return nil, fmt.Errorf("%w: invalid sort field %q", models.ErrBadRequest, sort)
+		}
+		query.SortBy = sort
+	}
+
+	switch order := params.Get("order"); order {
+	case "", "desc":
+		query.SortDesc = true
+	case "asc":
+	default:
+		return nil, fmt.Errorf("%w: invalid sort order %q", models.ErrBadRequest, order)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// memoryReviewRepo keeps reviews of movie 1 only, other movies don't exist
+type memoryReviewRepo struct {
+	reviews []*models.Review
+	votes   map[[2]int]bool
+}
+
+func (r *memoryReviewRepo) Create(ctx context.Context, review *models.Review) error {
+	if review.MovieID != 1 {
+		return models.ErrNotFound
+	}
+
+	for _, existing := range r.reviews {
+		if existing != nil && existing.UserID == review.UserID {
+			return models.ErrConflict
+		}
+	}
+
+	review.ID = len(r.reviews) + 1
+	review.CreatedAt = time.Now()
+	r.reviews = append(r.reviews, review)
+	return nil
+}
+
+func (r *memoryReviewRepo) Get(ctx context.Context, id int) (*models.Review, error) {
+	if id < 1 || id > len(r.reviews) || r.reviews[id-1] == nil {
+		return nil, models.ErrNotFound
+	}
+
+	copied := *r.reviews[id-1]
+	return &copied, nil
+}
+
+func (r *memoryReviewRepo) Update(ctx context.Context, review *models.Review) (*models.Review, error) {
+	stored := r.reviews[review.ID-1]
+	stored.Title, stored.Body, stored.Status = review.Title, review.Body, models.ReviewPending
+	return r.Get(ctx, review.ID)
+}
+
+func (r *memoryReviewRepo) Delete(ctx context.Context, id int) error {
+	r.reviews[id-1] = nil
+	return nil
+}
+
+func (r *memoryReviewRepo) List(ctx context.Context, q *models.ReviewQuery) ([]*models.Review, int, error) {
+	if q.MovieID > 1 {
+		return nil, 0, models.ErrNotFound
+	}
+
+	reviews := []*models.Review{}
+	for _, review := range r.reviews {
+		if review != nil && review.Status == q.Status {
+			reviews = append(reviews, review)
+		}
+	}
+
+	sort.Slice(reviews, func(i, j int) bool {
+		if q.SortBy == models.ReviewSortByHelpful {
+			return reviews[i].HelpfulCount > reviews[j].HelpfulCount
+		}
+		return reviews[i].ID > reviews[j].ID
+	})
+
+	return reviews, len(reviews), nil
+}
+
+func (r *memoryReviewRepo) Moderate(ctx context.Context, id int, from string, m *models.Moderation, moderator string) (*models.Review, error) {
+	stored := r.reviews[id-1]
+	if stored.Status != from {
+		return nil, models.ErrConflict
+	}
+
+	stored.Status, stored.ModerationNote, stored.ModeratedBy = m.Status, m.Note, moderator
+	return r.Get(ctx, id)
+}
+
+func (r *memoryReviewRepo) Vote(ctx context.Context, reviewID, userID int, vote *models.ReviewVote) error {
+	stored := r.reviews[reviewID-1]
+	if stored.Status != models.ReviewApproved {
+		return models.ErrNotFound
+	}
+
+	key := [2]int{reviewID, userID}
+	if helpful, ok := r.votes[key]; ok && helpful {
+		stored.HelpfulCount--
+	} else if ok {
+		stored.UnhelpfulCount--
+	}
+
+	r.votes[key] = vote.Helpful
+	if vote.Helpful {
+		stored.HelpfulCount++
+	} else {
+		stored.UnhelpfulCount++
+	}
+
+	return nil
+}
+
+func (r *memoryReviewRepo) DeleteVote(ctx context.Context, reviewID, userID int) error {
+	return nil
+}
+
+func TestReviewHandler_ModerationWorkflow(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "jwt-secret"}
+	logger := logger.NewLogger("local")
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{auth.UserSubject(3): models.RoleEditor}, logger))
+	reviewService := service.NewReviewService(&memoryReviewRepo{votes: map[[2]int]bool{}})
+	NewReviewHandler(reviewService, logger, "local").RegisterRoutes(router)
+
+	issuer := auth.NewIssuer(cfg, time.Minute)
+	tokenOf := func(userID int) string {
+		token, err := issuer.IssueUserToken(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	author, reader, moderator := tokenOf(1), tokenOf(2), tokenOf(3)
+
+	listApproved := func() *models.ReviewList {
+		w := serveJSON(router, "GET", "/movies/1/reviews?sort=helpful", "", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var list models.ReviewList
+		if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+			t.Fatal(err)
+		}
+		return &list
+	}
+
+	if w := serveJSON(router, "GET", "/movies/2/reviews", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected reviews of missing movie to be not found, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/movies/1/reviews", `{"title": "Great", "body": ""}`, author); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected empty review to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/movies/1/reviews", `{"title": "Great", "body": "Loved it"}`, author); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if list := listApproved(); list.Total != 0 {
+		t.Errorf("Expected pending review to be hidden, got %d reviews", list.Total)
+	}
+
+	if w := serveJSON(router, "GET", "/reviews/1", "", reader); w.Code != http.StatusNotFound {
+		t.Errorf("Expected pending review to be hidden from other users, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "GET", "/reviews/1", "", author); w.Code != http.StatusOK {
+		t.Errorf("Expected author to see pending review, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/reviews/1", `{"title": "Mine now", "body": "Hated it"}`, reader); w.Code != http.StatusForbidden {
+		t.Errorf("Expected only author to edit review, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/moderation/reviews/1", `{"status": "approved"}`, reader); w.Code != http.StatusForbidden {
+		t.Errorf("Expected viewer to be forbidden to moderate, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/moderation/reviews/1", `{"status": "pending"}`, moderator); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected moving back to pending to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/moderation/reviews/1", `{"status": "approved"}`, moderator); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveJSON(router, "PUT", "/moderation/reviews/1", `{"status": "approved"}`, moderator); w.Code != http.StatusConflict {
+		t.Errorf("Expected approving approved review to conflict, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/reviews/1/vote", `{"helpful": true}`, author); w.Code != http.StatusForbidden {
+		t.Errorf("Expected author to be forbidden to vote, got %d", w.Code)
+	}
+
+	serveJSON(router, "PUT", "/reviews/1/vote", `{"helpful": false}`, reader)
+	if w := serveJSON(router, "PUT", "/reviews/1/vote", `{"helpful": true}`, reader); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+
+	list := listApproved()
+	if list.Total != 1 || list.Reviews[0].HelpfulCount != 1 || list.Reviews[0].UnhelpfulCount != 0 {
+		t.Errorf("Expected approved review with changed vote, got %+v", list.Reviews)
+	}
+
+	if w := serveJSON(router, "PUT", "/reviews/1", `{"title": "Great", "body": "Still love it"}`, author); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	if list := listApproved(); list.Total != 0 {
+		t.Errorf("Expected edited review to need approval again, got %d reviews", list.Total)
+	}
+
+	w := serveJSON(router, "GET", "/moderation/reviews", "", moderator)
+	var queue models.ReviewList
+	if err := json.NewDecoder(w.Body).Decode(&queue); err != nil {
+		t.Fatal(err)
+	}
+	if queue.Total != 1 || queue.Reviews[0].Status != models.ReviewPending {
+		t.Errorf("Expected edited review in moderation queue, got %+v", queue.Reviews)
+	}
+}
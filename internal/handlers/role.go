@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type RoleHandler struct {
+	service service.RoleService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewRoleHandler(service service.RoleService, log *slog.Logger, env string) *RoleHandler {
+	return &RoleHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers role management routes, service lets through only admins
+func (h *RoleHandler) RegisterRoutes(router *mux.Router) {
+	admin := router.NewRoute().Subrouter()
+	admin.Use(middleware.RequireAuth)
+	admin.HandleFunc("/admin/roles", h.ListRoles).Methods("GET")
+	admin.HandleFunc("/admin/roles/{subject}", h.SetRole).Methods("PUT")
+	admin.HandleFunc("/admin/roles/{subject}", h.DeleteRole).Methods("DELETE")
+}
+
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.ListRoles(r.Context())
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list roles", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(roles)
+}
+
+func (h *RoleHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	var assignment models.RoleAssignment
+
+	if err := decodeJSON(r, &assignment); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode role body", "error", err)
+		return
+	}
+	assignment.Subject = mux.Vars(r)["subject"]
+
+	if err := h.service.SetRole(r.Context(), &assignment); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to set role", "subject", assignment.Subject, "error", err)
+		return
+	}
+
+	h.log.Info("Role set succesfully", "subject", assignment.Subject, "role", assignment.Role)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assignment)
+}
+
+func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	subject := mux.Vars(r)["subject"]
+
+	if err := h.service.DeleteRole(r.Context(), subject); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to delete role", "subject", subject, "error", err)
+		return
+	}
+
+	h.log.Info("Role was deleted succesfully", "subject", subject)
+	w.WriteHeader(http.StatusNoContent)
+}
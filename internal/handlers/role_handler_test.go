@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type memoryRoleRepo map[string]*models.RoleAssignment
+
+func (r memoryRoleRepo) Get(ctx context.Context, subject string) (*models.RoleAssignment, error) {
+	if a, ok := r[subject]; ok {
+		return a, nil
+	}
+
+	return nil, models.ErrNotFound
+}
+
+func (r memoryRoleRepo) List(ctx context.Context) ([]*models.RoleAssignment, error) {
+	list := []*models.RoleAssignment{}
+	for _, a := range r {
+		list = append(list, a)
+	}
+
+	return list, nil
+}
+
+func (r memoryRoleRepo) Set(ctx context.Context, a *models.RoleAssignment) error {
+	r[a.Subject] = a
+	return nil
+}
+
+func (r memoryRoleRepo) Delete(ctx context.Context, subject string) error {
+	if _, ok := r[subject]; !ok {
+		return models.ErrNotFound
+	}
+
+	delete(r, subject)
+	return nil
+}
+
+func TestRoleHandler_SetRole(t *testing.T) {
+	repo := memoryRoleRepo{}
+	roleService := service.NewRoleService(repo, []string{"root"})
+	handler := NewRoleHandler(roleService, logger.NewLogger("local"), "local")
+
+	tests := []struct {
+		role   string
+		body   string
+		status int
+	}{
+		{models.RoleEditor, `{"role": "editor"}`, http.StatusForbidden},
+		{models.RoleAdmin, `{"role": "owner"}`, http.StatusUnprocessableEntity},
+		{models.RoleAdmin, `{"role": "editor"}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		router := mux.NewRouter()
+		router.Use(authenticatedAs(tt.role))
+		handler.RegisterRoutes(router)
+
+		req := httptest.NewRequest("PUT", "/admin/roles/bob", bytes.NewReader([]byte(tt.body)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("Role %s setting %s: expected status %d, got %d", tt.role, tt.body, tt.status, w.Code)
+		}
+	}
+
+	role, err := roleService.ResolveRole(context.Background(), "bob")
+	if err != nil || role != models.RoleEditor {
+		t.Errorf("Expected bob to be editor, got %q (%v)", role, err)
+	}
+
+	role, _ = roleService.ResolveRole(context.Background(), "root")
+	if role != models.RoleAdmin {
+		t.Errorf("Expected bootstrap subject to be admin, got %q", role)
+	}
+
+	role, _ = roleService.ResolveRole(context.Background(), "carol")
+	if role != models.RoleViewer {
+		t.Errorf("Expected subject without role to be viewer, got %q", role)
+	}
+}
+
+func TestRoleHandler_ListRoles(t *testing.T) {
+	repo := memoryRoleRepo{"bob": {Subject: "bob", Role: models.RoleEditor}}
+	handler := NewRoleHandler(service.NewRoleService(repo, nil), logger.NewLogger("local"), "local")
+
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/roles", nil))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous caller to get 401, got %d", w.Code)
+	}
+
+	router = mux.NewRouter()
+	router.Use(authenticatedAs(models.RoleAdmin))
+	handler.RegisterRoutes(router)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/roles", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var roles []*models.RoleAssignment
+	if err := json.Unmarshal(w.Body.Bytes(), &roles); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(roles) != 1 || roles[0].Subject != "bob" {
+		t.Errorf("Expected bob's role, got %+v", roles)
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type TagHandler struct {
+	service service.TagService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewTagHandler(service service.TagService, log *slog.Logger, env string) *TagHandler {
+	return &TagHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers tag routes, reads are public and changes of movie tags require authenticated caller
+func (h *TagHandler) RegisterRoutes(router *mux.Router) {
+	writes := router.NewRoute().Subrouter()
+	writes.Use(middleware.RequireAuth)
+	writes.HandleFunc("/movies/{id}/tags", h.AttachTags).Methods("POST")
+	writes.HandleFunc("/movies/{id}/tags/{tag}", h.DetachTag).Methods("DELETE")
+
+	router.HandleFunc("/movies/{id}/tags", h.ListMovieTags).Methods("GET")
+	router.HandleFunc("/tags/suggest", h.SuggestTags).Methods("GET")
+	router.HandleFunc("/tags/cloud", h.TagCloud).Methods("GET")
+}
+
+func (h *TagHandler) AttachTags(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	var tags models.MovieTags
+	if err := decodeJSON(r, &tags); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode tags body", "error", err)
+		return
+	}
+
+	attached, err := h.service.AttachTags(r.Context(), id, &tags)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to attach tags", "ID", id, "error", err)
+		return
+	}
+
+	h.log.Info("Tags attached succesfully", "ID", id, "tags", tags.Tags)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.MovieTags{Tags: attached})
+}
+
+func (h *TagHandler) DetachTag(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	if err := h.service.DetachTag(r.Context(), id, vars["tag"]); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to detach tag", "ID", id, "tag", vars["tag"], "error", err)
+		return
+	}
+
+	h.log.Info("Tag was detached succesfully", "ID", id, "tag", vars["tag"])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TagHandler) ListMovieTags(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	tags, err := h.service.ListMovieTags(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list movie tags", "ID", id, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.MovieTags{Tags: tags})
+}
+
+func (h *TagHandler) SuggestTags(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	text := strings.TrimSpace(params.Get("q"))
+	if text == "" {
+		h.writeError(w, r, fmt.Errorf("%w: search query is required", models.ErrBadRequest))
+		h.log.Error("Empty tag suggest query")
+		return
+	}
+
+	limit, err := parseIntParam(params, "limit")
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid tag suggest query", "error", err)
+		return
+	}
+
+	tags, err := h.service.SuggestTags(r.Context(), text, limit)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to suggest tags", "error", err)
+		return
+	}
+
+	h.log.Debug("Tags suggested", "count", len(tags))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
+
+func (h *TagHandler) TagCloud(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r.URL.Query(), "limit")
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Invalid tag cloud query", "error", err)
+		return
+	}
+
+	tags, err := h.service.TagCloud(r.Context(), limit)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get tag cloud", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
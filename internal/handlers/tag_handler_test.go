@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/movie"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// memoryTagRepo keeps sorted tag names of movies
+type memoryTagRepo struct {
+	tags map[int][]string
+}
+
+// memoryTagMovies changes tags of memoryTagRepo as movie repository update, movies 1 to 3 exist
+type memoryTagMovies struct {
+	movie.Repository
+	repo     *memoryTagRepo
+	versions map[int]int
+}
+
+func (r *memoryTagMovies) Update(ctx context.Context, id int, update func(*models.Movie) error) (*models.Movie, error) {
+	if id < 1 || id > 3 {
+		return nil, models.ErrNotFound
+	}
+
+	m := &models.Movie{ID: id, Version: r.versions[id], Tags: slices.Clone(r.repo.tags[id])}
+	if err := update(m); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(m.Tags)
+	m.Version++
+	r.repo.tags[id], r.versions[id] = m.Tags, m.Version
+
+	return m, nil
+}
+
+func (r *memoryTagRepo) ListByMovie(ctx context.Context, movieID int) ([]string, error) {
+	return r.tags[movieID], nil
+}
+
+func (r *memoryTagRepo) Suggest(ctx context.Context, prefix string, limit int) ([]*models.Tag, error) {
+	tags := []*models.Tag{}
+	for _, tag := range r.usage() {
+		if strings.HasPrefix(tag.Name, prefix) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+func (r *memoryTagRepo) MostUsed(ctx context.Context, limit int) ([]*models.Tag, error) {
+	return r.usage(), nil
+}
+
+func (r *memoryTagRepo) usage() []*models.Tag {
+	counts := map[string]int{}
+	for _, names := range r.tags {
+		for _, name := range names {
+			counts[name]++
+		}
+	}
+
+	tags := []*models.Tag{}
+	for name, count := range counts {
+		tags = append(tags, &models.Tag{Name: name, Count: count})
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags
+}
+
+func TestTagHandler_Tags(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "jwt-secret"}
+	logger := logger.NewLogger("local")
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{"editor": models.RoleEditor}, logger))
+	repo := &memoryTagRepo{tags: map[int][]string{}}
+	movies := &memoryTagMovies{repo: repo, versions: map[int]int{}}
+	NewTagHandler(service.NewTagService(repo, movies), logger, "local").RegisterRoutes(router)
+
+	editor := signToken(t, "jwt-secret", "editor", time.Minute)
+	viewer := signToken(t, "jwt-secret", "viewer", time.Minute)
+
+	if w := serveJSON(router, "POST", "/movies/1/tags", `{"tags": ["heist"]}`, viewer); w.Code != http.StatusForbidden {
+		t.Errorf("Expected viewer to be forbidden, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/movies/1/tags", `{"tags": ["  "]}`, editor); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected blank tags to be rejected, got %d", w.Code)
+	}
+
+	w := serveJSON(router, "POST", "/movies/1/tags", `{"tags": ["Time  Travel", "heist", "time travel"]}`, editor)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var attached models.MovieTags
+	if err := json.NewDecoder(w.Body).Decode(&attached); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(attached.Tags, []string{"heist", "time travel"}) {
+		t.Errorf("Expected normalized tags, got %v", attached.Tags)
+	}
+
+	if w := serveJSON(router, "POST", "/movies/2/tags", `{"tags": ["time travel"]}`, editor); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveJSON(router, "GET", "/tags/suggest", "", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected empty suggest query to be rejected, got %d", w.Code)
+	}
+
+	w = serveJSON(router, "GET", "/tags/cloud", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var cloud []*models.Tag
+	if err := json.NewDecoder(w.Body).Decode(&cloud); err != nil {
+		t.Fatal(err)
+	}
+	if len(cloud) != 2 || cloud[0].Name != "time travel" || cloud[0].Count != 2 {
+		t.Errorf("Expected time travel to be the most used tag, got %+v", cloud)
+	}
+
+	if w := serveJSON(router, "DELETE", "/movies/1/tags/Time%20Travel", "", editor); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveJSON(router, "DELETE", "/movies/1/tags/noir", "", editor); w.Code != http.StatusNotFound {
+		t.Errorf("Expected missing tag to give 404, got %d", w.Code)
+	}
+
+	if movies.versions[1] != 2 {
+		t.Errorf("Expected each tag change to bump movie version, got version %d", movies.versions[1])
+	}
+
+	if w := serveJSON(router, "POST", "/movies/4/tags", `{"tags": ["heist"]}`, editor); w.Code != http.StatusNotFound {
+		t.Errorf("Expected attaching to missing movie to give 404, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "DELETE", "/movies/4/tags/heist", "", editor); w.Code != http.StatusNotFound {
+		t.Errorf("Expected detaching from missing movie to give 404, got %d", w.Code)
+	}
+
+	w = serveJSON(router, "GET", "/tags/suggest?q=TIME", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var suggestions []*models.Tag
+	if err := json.NewDecoder(w.Body).Decode(&suggestions); err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Count != 1 {
+		t.Errorf("Expected time travel to be left on one movie, got %+v", suggestions)
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type UserHandler struct {
+	service service.UserService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewUserHandler(service service.UserService, log *slog.Logger, env string) *UserHandler {
+	return &UserHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers account routes, password change and profile need user access token
+func (h *UserHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/auth/register", h.Register).Methods("POST")
+	router.HandleFunc("/auth/login", h.Login).Methods("POST")
+	router.HandleFunc("/auth/refresh", h.Refresh).Methods("POST")
+	router.HandleFunc("/auth/logout", h.Logout).Methods("POST")
+
+	users := router.NewRoute().Subrouter()
+	users.Use(middleware.RequireAuth)
+	users.HandleFunc("/auth/password", h.ChangePassword).Methods("PUT")
+	users.HandleFunc("/auth/me", h.CurrentUser).Methods("GET")
+}
+
+func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var reg models.Registration
+
+	if err := decodeJSON(r, &reg); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode registration body", "error", err)
+		return
+	}
+
+	user, err := h.service.Register(r.Context(), &reg)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to register user", "error", err)
+		return
+	}
+
+	h.log.Info("User registered succesfully", "ID", user.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var credentials models.Credentials
+
+	if err := decodeJSON(r, &credentials); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode credentials body", "error", err)
+		return
+	}
+
+	tokens, err := h.service.Login(r.Context(), &credentials)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to login", "error", err)
+		return
+	}
+
+	writeTokens(w, tokens)
+}
+
+func (h *UserHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode refresh body", "error", err)
+		return
+	}
+
+	tokens, err := h.service.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to refresh tokens", "error", err)
+		return
+	}
+
+	writeTokens(w, tokens)
+}
+
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode logout body", "error", err)
+		return
+	}
+
+	if err := h.service.Logout(r.Context(), req.RefreshToken); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to logout", "error", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var change models.PasswordChange
+
+	if err := decodeJSON(r, &change); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode password body", "error", err)
+		return
+	}
+
+	if err := h.service.ChangePassword(r.Context(), &change); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to change password", "error", err)
+		return
+	}
+
+	h.log.Info("Password changed succesfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) CurrentUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.service.CurrentUser(r.Context())
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to get current user", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+func writeTokens(w http.ResponseWriter, tokens *models.TokenPair) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokens)
+}
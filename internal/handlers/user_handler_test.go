@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type memoryUserRepo struct {
+	users  []*models.User
+	tokens []*models.RefreshToken
+}
+
+func (r *memoryUserRepo) Create(ctx context.Context, user *models.User) error {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Email, user.Email) {
+			return models.ErrConflict
+		}
+	}
+
+	user.ID = len(r.users) + 1
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *memoryUserRepo) GetByID(ctx context.Context, id int) (*models.User, error) {
+	if id < 1 || id > len(r.users) {
+		return nil, models.ErrNotFound
+	}
+
+	return r.users[id-1], nil
+}
+
+func (r *memoryUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Email, email) {
+			return u, nil
+		}
+	}
+
+	return nil, models.ErrNotFound
+}
+
+func (r *memoryUserRepo) UpdatePassword(ctx context.Context, id int, hash string) error {
+	r.users[id-1].PasswordHash = hash
+	return nil
+}
+
+func (r *memoryUserRepo) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	token.ID = len(r.tokens) + 1
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryUserRepo) GetRefreshToken(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.Hash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+
+	return nil, models.ErrNotFound
+}
+
+func (r *memoryUserRepo) RotateRefreshToken(ctx context.Context, id int, next *models.RefreshToken) error {
+	token := r.tokens[id-1]
+	if token.RevokedAt != nil {
+		return models.ErrNotFound
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	return r.CreateRefreshToken(ctx, next)
+}
+
+func (r *memoryUserRepo) RevokeRefreshFamily(ctx context.Context, familyID string) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *memoryUserRepo) RevokeUserRefreshTokens(ctx context.Context, userID int) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func newUserTestRouter(t *testing.T) *mux.Router {
+	t.Helper()
+
+	cfg := &config.Config{JWTSecret: "jwt-secret"}
+	logger := logger.NewLogger("local")
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userService := service.NewUserService(&memoryUserRepo{}, auth.NewIssuer(cfg, service.AccessTokenTTL))
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{}, logger))
+	NewUserHandler(userService, logger, "local").RegisterRoutes(router)
+
+	return router
+}
+
+func serveJSON(router *mux.Router, method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeTokens(t *testing.T, w *httptest.ResponseRecorder) *models.TokenPair {
+	t.Helper()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var tokens models.TokenPair
+	if err := json.Unmarshal(w.Body.Bytes(), &tokens); err != nil {
+		t.Fatal(err)
+	}
+
+	return &tokens
+}
+
+func TestUserHandler_Register(t *testing.T) {
+	router := newUserTestRouter(t)
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"email": "alice@example.com", "name": "Alice", "password": "correct horse"}`, http.StatusCreated},
+		{`{"email": "ALICE@example.com", "password": "correct horse"}`, http.StatusConflict},
+		{`{"email": "not an email", "password": "correct horse"}`, http.StatusUnprocessableEntity},
+		{`{"email": "bob@example.com", "password": "short"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		w := serveJSON(router, "POST", "/auth/register", tt.body, "")
+
+		if w.Code != tt.status {
+			t.Errorf("Registering %s: expected status %d, got %d", tt.body, tt.status, w.Code)
+		}
+
+		if strings.Contains(w.Body.String(), "correct horse") || strings.Contains(w.Body.String(), "password_hash") {
+			t.Errorf("Registering %s: response leaks password: %s", tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestUserHandler_LoginAndRefresh(t *testing.T) {
+	router := newUserTestRouter(t)
+
+	serveJSON(router, "POST", "/auth/register", `{"email": "alice@example.com", "password": "correct horse"}`, "")
+
+	if w := serveJSON(router, "POST", "/auth/login", `{"email": "alice@example.com", "password": "wrong horse"}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected wrong password to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/auth/login", `{"email": "bob@example.com", "password": "correct horse"}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected unknown email to be rejected, got %d", w.Code)
+	}
+
+	login := decodeTokens(t, serveJSON(router, "POST", "/auth/login", `{"email": "alice@example.com", "password": "correct horse"}`, ""))
+
+	w := serveJSON(router, "GET", "/auth/me", "", login.AccessToken)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "alice@example.com") {
+		t.Errorf("Expected access token to identify alice, got %d: %s", w.Code, w.Body.String())
+	}
+
+	refreshed := decodeTokens(t, serveJSON(router, "POST", "/auth/refresh", `{"refresh_token": "`+login.RefreshToken+`"}`, ""))
+
+	if refreshed.RefreshToken == login.RefreshToken {
+		t.Error("Expected refresh token to be rotated")
+	}
+
+	// reuse of rotated token revokes the token it was rotated into as well
+	if w := serveJSON(router, "POST", "/auth/refresh", `{"refresh_token": "`+login.RefreshToken+`"}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected rotated token to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/auth/refresh", `{"refresh_token": "`+refreshed.RefreshToken+`"}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected token family to be revoked after reuse, got %d", w.Code)
+	}
+}
+
+func TestUserHandler_LoginWithoutIssuer(t *testing.T) {
+	repo := &memoryUserRepo{}
+	logger := logger.NewLogger("local")
+	userService := service.NewUserService(repo, auth.NewIssuer(&config.Config{}, service.AccessTokenTTL))
+
+	router := mux.NewRouter()
+	NewUserHandler(userService, logger, "local").RegisterRoutes(router)
+
+	serveJSON(router, "POST", "/auth/register", `{"email": "alice@example.com", "password": "correct horse"}`, "")
+
+	if w := serveJSON(router, "POST", "/auth/login", `{"email": "alice@example.com", "password": "correct horse"}`, ""); w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+
+	if len(repo.tokens) != 0 {
+		t.Errorf("Expected no refresh tokens to be stored, got %d", len(repo.tokens))
+	}
+}
+
+func TestUserHandler_LogoutAndChangePassword(t *testing.T) {
+	router := newUserTestRouter(t)
+
+	serveJSON(router, "POST", "/auth/register", `{"email": "alice@example.com", "password": "correct horse"}`, "")
+	login := func(password string) *httptest.ResponseRecorder {
+		return serveJSON(router, "POST", "/auth/login", `{"email": "alice@example.com", "password": "`+password+`"}`, "")
+	}
+
+	first := decodeTokens(t, login("correct horse"))
+
+	if w := serveJSON(router, "POST", "/auth/logout", `{"refresh_token": "`+first.RefreshToken+`"}`, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/auth/refresh", `{"refresh_token": "`+first.RefreshToken+`"}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected logged out token to be rejected, got %d", w.Code)
+	}
+
+	second := decodeTokens(t, login("correct horse"))
+
+	if w := serveJSON(router, "PUT", "/auth/password", `{"current_password": "wrong horse", "new_password": "battery staple"}`, second.AccessToken); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected wrong current password to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/auth/password", `{"current_password": "correct horse", "new_password": "battery staple"}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous password change to be rejected, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "PUT", "/auth/password", `{"current_password": "correct horse", "new_password": "battery staple"}`, second.AccessToken); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "POST", "/auth/refresh", `{"refresh_token": "`+second.RefreshToken+`"}`, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected refresh tokens to be revoked after password change, got %d", w.Code)
+	}
+
+	if w := login("correct horse"); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected old password to be rejected, got %d", w.Code)
+	}
+
+	decodeTokens(t, login("battery staple"))
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type WatchlistHandler struct {
+	service service.WatchlistService
+	log     *slog.Logger
+	errorWriter
+}
+
+func NewWatchlistHandler(service service.WatchlistService, log *slog.Logger, env string) *WatchlistHandler {
+	return &WatchlistHandler{service: service, log: log, errorWriter: newErrorWriter(env)}
+}
+
+// RegisterRoutes registers watchlist and history routes, service lets through owners of lists and admins
+func (h *WatchlistHandler) RegisterRoutes(router *mux.Router) {
+	users := router.NewRoute().Subrouter()
+	users.Use(middleware.RequireAuth)
+	users.HandleFunc("/users/{id}/watchlist", h.ListWatchlist).Methods("GET")
+	users.HandleFunc("/users/{id}/watchlist", h.AddToWatchlist).Methods("POST")
+	users.HandleFunc("/users/{id}/watchlist/order", h.ReorderWatchlist).Methods("PUT")
+	users.HandleFunc("/users/{id}/watchlist/{movieId}", h.RemoveFromWatchlist).Methods("DELETE")
+	users.HandleFunc("/users/{id}/history", h.ListHistory).Methods("GET")
+	users.HandleFunc("/users/{id}/history", h.AddToHistory).Methods("POST")
+	users.HandleFunc("/users/{id}/history/order", h.ReorderHistory).Methods("PUT")
+	users.HandleFunc("/users/{id}/history/{movieId}", h.RemoveFromHistory).Methods("DELETE")
+}
+
+func (h *WatchlistHandler) ListWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid user id", models.ErrBadRequest))
+		h.log.Error("Invalid user id", "error", err)
+		return
+	}
+
+	items, err := h.service.ListWatchlist(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list watchlist", "user", userID, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+func (h *WatchlistHandler) AddToWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid user id", models.ErrBadRequest))
+		h.log.Error("Invalid user id", "error", err)
+		return
+	}
+
+	var item models.WatchlistItem
+	if err := decodeJSON(r, &item); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode watchlist item body", "error", err)
+		return
+	}
+
+	if err := h.service.AddToWatchlist(r.Context(), userID, &item); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to add movie to watchlist", "user", userID, "error", err)
+		return
+	}
+
+	h.log.Info("Movie added to watchlist succesfully", "user", userID, "movie", item.MovieID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *WatchlistHandler) ReorderWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid user id", models.ErrBadRequest))
+		h.log.Error("Invalid user id", "error", err)
+		return
+	}
+
+	var order models.ListOrder
+	if err := decodeJSON(r, &order); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode order body", "error", err)
+		return
+	}
+
+	if err := h.service.ReorderWatchlist(r.Context(), userID, &order); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to reorder watchlist", "user", userID, "error", err)
+		return
+	}
+
+	h.log.Info("Watchlist reordered succesfully", "user", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WatchlistHandler) RemoveFromWatchlist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid user id", models.ErrBadRequest))
+		h.log.Error("Invalid user id", "error", err)
+		return
+	}
+
+	movieID, err := strconv.Atoi(vars["movieId"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	if err := h.service.RemoveFromWatchlist(r.Context(), userID, movieID); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to remove movie from watchlist", "user", userID, "movie", movieID, "error", err)
+		return
+	}
+
+	h.log.Info("Movie was removed from watchlist succesfully", "user", userID, "movie", movieID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WatchlistHandler) ListHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid user id", models.ErrBadRequest))
+		h.log.Error("Invalid user id", "error", err)
+		return
+	}
+
+	entries, err := h.service.ListHistory(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to list history", "user", userID, "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+func (h *WatchlistHandler) AddToHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid user id", models.ErrBadRequest))
+		h.log.Error("Invalid user id", "error", err)
+		return
+	}
+
+	var entry models.HistoryEntry
+	if err := decodeJSON(r, &entry); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode history entry body", "error", err)
+		return
+	}
+
+	if err := h.service.AddToHistory(r.Context(), userID, &entry); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to add movie to history", "user", userID, "error", err)
+		return
+	}
+
+	h.log.Info("Movie added to history succesfully", "user", userID, "movie", entry.MovieID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+func (h *WatchlistHandler) ReorderHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid user id", models.ErrBadRequest))
+		h.log.Error("Invalid user id", "error", err)
+		return
+	}
+
+	var order models.ListOrder
+	if err := decodeJSON(r, &order); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to decode order body", "error", err)
+		return
+	}
+
+	if err := h.service.ReorderHistory(r.Context(), userID, &order); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to reorder history", "user", userID, "error", err)
+		return
+	}
+
+	h.log.Info("History reordered succesfully", "user", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WatchlistHandler) RemoveFromHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid user id", models.ErrBadRequest))
+		h.log.Error("Invalid user id", "error", err)
+		return
+	}
+
+	movieID, err := strconv.Atoi(vars["movieId"])
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid movie id", models.ErrBadRequest))
+		h.log.Error("Invalid movie id", "error", err)
+		return
+	}
+
+	if err := h.service.RemoveFromHistory(r.Context(), userID, movieID); err != nil {
+		h.writeError(w, r, err)
+		h.log.Error("Failed to remove movie from history", "user", userID, "movie", movieID, "error", err)
+		return
+	}
+
+	h.log.Info("Movie was removed from history succesfully", "user", userID, "movie", movieID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/config"
+	"github.com/CAATHARSIS/movies-library/internal/logger"
+	"github.com/CAATHARSIS/movies-library/internal/middleware"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// memoryWatchlistRepo keeps watchlists only, movies 1 to 3 exist
+type memoryWatchlistRepo struct {
+	items map[int][]*models.WatchlistItem
+}
+
+func (r *memoryWatchlistRepo) AddToWatchlist(ctx context.Context, userID int, item *models.WatchlistItem) error {
+	if item.MovieID < 1 || item.MovieID > 3 {
+		return models.ErrNotFound
+	}
+
+	for _, existing := range r.items[userID] {
+		if existing.MovieID == item.MovieID {
+			*item = *existing
+			return nil
+		}
+	}
+
+	item.Position = len(r.items[userID])
+	r.items[userID] = append(r.items[userID], item)
+	return nil
+}
+
+func (r *memoryWatchlistRepo) RemoveFromWatchlist(ctx context.Context, userID, movieID int) error {
+	for i, item := range r.items[userID] {
+		if item.MovieID == movieID {
+			r.items[userID] = append(r.items[userID][:i], r.items[userID][i+1:]...)
+			return nil
+		}
+	}
+
+	return models.ErrNotFound
+}
+
+func (r *memoryWatchlistRepo) ListWatchlist(ctx context.Context, userID int) ([]*models.WatchlistItem, error) {
+	items := append([]*models.WatchlistItem{}, r.items[userID]...)
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Position < items[j].Position
+	})
+	return items, nil
+}
+
+func (r *memoryWatchlistRepo) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int, check func([]int) error) error {
+	current := []int{}
+	for _, item := range r.items[userID] {
+		current = append(current, item.MovieID)
+	}
+
+	if err := check(current); err != nil {
+		return err
+	}
+
+	for position, movieID := range movieIDs {
+		for _, item := range r.items[userID] {
+			if item.MovieID == movieID {
+				item.Position = position
+			}
+		}
+	}
+	return nil
+}
+
+func (r *memoryWatchlistRepo) AddToHistory(ctx context.Context, userID int, entry *models.HistoryEntry) error {
+	return nil
+}
+
+func (r *memoryWatchlistRepo) RemoveFromHistory(ctx context.Context, userID, movieID int) error {
+	return nil
+}
+
+func (r *memoryWatchlistRepo) ListHistory(ctx context.Context, userID int) ([]*models.HistoryEntry, error) {
+	return []*models.HistoryEntry{}, nil
+}
+
+func (r *memoryWatchlistRepo) ReorderHistory(ctx context.Context, userID int, movieIDs []int, check func([]int) error) error {
+	return nil
+}
+
+func TestWatchlistHandler_Watchlist(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "jwt-secret"}
+	logger := logger.NewLogger("local")
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	router.Use(middleware.NewAuthMiddleware(verifier, staticRoles{auth.UserSubject(3): models.RoleAdmin}, logger))
+	watchlistService := service.NewWatchlistService(&memoryWatchlistRepo{items: map[int][]*models.WatchlistItem{}})
+	NewWatchlistHandler(watchlistService, logger, "local").RegisterRoutes(router)
+
+	issuer := auth.NewIssuer(cfg, time.Minute)
+	tokenOf := func(userID int) string {
+		token, err := issuer.IssueUserToken(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	owner, stranger, admin := tokenOf(1), tokenOf(2), tokenOf(3)
+
+	for _, movieID := range []string{"1", "2", "3", "1"} {
+		if w := serveJSON(router, "POST", "/users/1/watchlist", `{"movie_id": `+movieID+`}`, owner); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	if w := serveJSON(router, "POST", "/users/1/watchlist", `{"movie_id": 42}`, owner); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for missing movie, got %d", w.Code)
+	}
+
+	if w := serveJSON(router, "GET", "/users/1/watchlist", "", stranger); w.Code != http.StatusForbidden {
+		t.Errorf("Expected other users to be forbidden, got %d", w.Code)
+	}
+
+	for _, order := range []string{`[3, 1]`, `[3, 1, 1]`, `[3, 1, 42]`} {
+		if w := serveJSON(router, "PUT", "/users/1/watchlist/order", `{"movie_ids": `+order+`}`, owner); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected order %s to be rejected, got %d", order, w.Code)
+		}
+	}
+
+	if w := serveJSON(router, "PUT", "/users/1/watchlist/order", `{"movie_ids": [3, 1, 2]}`, owner); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := serveJSON(router, "DELETE", "/users/1/watchlist/1", "", owner); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+
+	w := serveJSON(router, "GET", "/users/1/watchlist", "", admin)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected admin to read watchlist, got %d", w.Code)
+	}
+
+	var items []*models.WatchlistItem
+	if err := json.NewDecoder(w.Body).Decode(&items); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 || items[0].MovieID != 3 || items[1].MovieID != 2 {
+		t.Errorf("Expected movies 3 and 2 in this order, got %+v", items)
+	}
+
+	if w := serveJSON(router, "POST", "/users/1/history", `{"movie_id": 1, "rewatch_count": -1}`, owner); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected negative rewatch count to be rejected, got %d", w.Code)
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/problem"
+)
+
+// APIKeyHeader carries key of machine clients, "Authorization: ApiKey <key>" is accepted as well
+const APIKeyHeader = "X-API-Key"
+
+// NewAPIKeyMiddleware function puts principal of valid API key into request context.
+// Like with bearer tokens, requests with invalid key go on anonymously and are stopped by RequireAuth
+func NewAPIKeyMiddleware(keys auth.KeyAuthenticator, log *slog.Logger) func(next http.Handler) http.Handler {
+	log = log.With(
+		slog.String("component", "middleware/apikey"),
+	)
+
+	log.Info("api key middleware is enabled")
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := apiKey(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := keys.AuthenticateAPIKey(r.Context(), key)
+			if errors.Is(err, models.ErrUnauthorized) {
+				log.Debug("API key is rejected", "request_id", GetReqID(r.Context()), "error", err)
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authErrorKey{}, err)))
+				return
+			}
+			if err != nil {
+				log.Error("Failed to authenticate api key", "request_id", GetReqID(r.Context()), "error", err)
+				writeProblem(w, r, problem.New(problem.TypeInternal, http.StatusInternalServerError, ""))
+				return
+			}
+
+			audit.SetActor(r.Context(), principal.Subject)
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func apiKey(r *http.Request) string {
+	if key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "ApiKey "); ok {
+		return key
+	}
+
+	return r.Header.Get(APIKeyHeader)
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+// NewAuditMiddleware function stores every POST, PUT, PATCH and DELETE request into sink.
+// Handlers and services fill entity and its snapshots through request context
+func NewAuditMiddleware(sink audit.Sink, log *slog.Logger) func(next http.Handler) http.Handler {
+	log = log.With(
+		slog.String("component", "middleware/audit"),
+	)
+
+	log.Info("audit middleware is enabled")
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			entry := &models.AuditEntry{
+				Actor:     audit.Anonymous,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				RequestID: GetReqID(r.Context()),
+				SourceIP:  sourceIP(r),
+			}
+
+			ww := &responseWriteWrapper{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+
+			next.ServeHTTP(ww, r.WithContext(audit.NewContext(r.Context(), entry)))
+
+			entry.Status = ww.Status()
+
+			// client may be gone already, but the call must be recorded anyway
+			if err := sink.Create(context.WithoutCancel(r.Context()), entry); err != nil {
+				log.Error("Failed to store audit entry", "request_id", entry.RequestID, "error", err)
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/problem"
+)
+
+type authErrorKey struct{}
+
+// NewAuthMiddleware function puts principal of valid bearer token with its role into request context.
+// Requests without token or with invalid one go on anonymously, RequireAuth stops them where needed
+func NewAuthMiddleware(verifier *auth.Verifier, roles auth.RoleResolver, log *slog.Logger) func(next http.Handler) http.Handler {
+	log = log.With(
+		slog.String("component", "middleware/auth"),
+	)
+
+	log.Info("auth middleware is enabled")
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := verifier.Verify(token)
+			if err != nil {
+				log.Debug("Bearer token is rejected", "request_id", GetReqID(r.Context()), "error", err)
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authErrorKey{}, err)))
+				return
+			}
+
+			principal.Role, err = roles.ResolveRole(r.Context(), principal.Subject)
+			if err != nil {
+				log.Error("Failed to resolve role", "subject", principal.Subject, "error", err)
+				writeProblem(w, r, problem.New(problem.TypeInternal, http.StatusInternalServerError, ""))
+				return
+			}
+
+			audit.SetActor(r.Context(), principal.Subject)
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequireAuth lets through only requests authenticated by bearer token or API key
+func RequireAuth(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.FromContext(r.Context()); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err, ok := r.Context().Value(authErrorKey{}).(error); ok {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeProblem(w, r, problem.New(problem.TypeUnauthorized, http.StatusUnauthorized, err.Error()))
+			return
+		}
+
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeProblem(w, r, problem.New(problem.TypeUnauthorized, http.StatusUnauthorized, "bearer token is required"))
+	}
+	return http.HandlerFunc(fn)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, p *problem.Problem) {
+	p.Instance = r.URL.Path
+	p.RequestID = GetReqID(r.Context())
+	problem.Write(w, p)
+}
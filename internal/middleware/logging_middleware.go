@@ -1,4 +1,4 @@
-// Package middleware provides logging
+// Package middleware provides logging and request ids
 package middleware
 
 import (
@@ -50,7 +50,7 @@ func NewLoggingMiddleware(log *slog.Logger) func(next http.Handler) http.Handler
 				slog.String("path", r.URL.Path),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
-				// slog.String("request_id", GetReqID(r.Context())),
+				slog.String("request_id", GetReqID(r.Context())),
 			)
 
 			ww := &responseWriteWrapper{
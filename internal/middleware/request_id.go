@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is read from request and set in response, so calls can be traced across services
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds ids accepted from clients
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// NewRequestIDMiddleware function assigns id to every request, id sent by client is kept
+func NewRequestIDMiddleware() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" || len(id) > maxRequestIDLength {
+				id = newRequestID()
+			}
+
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// GetReqID returns id of request, it's empty when request id middleware is not used
+func GetReqID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Scopes which API keys can carry, each of them allows actions of auth policy
+const (
+	ScopeMoviesRead      = "movies:read"
+	ScopeMoviesWrite     = "movies:write"
+	ScopeMoviesDelete    = "movies:delete"
+	ScopeReviewsModerate = "reviews:moderate"
+	ScopeUsersManage     = "users:manage"
+	ScopeAuditRead       = "audit:read"
+	ScopeKeysManage      = "keys:manage"
+)
+
+// APIKey authenticates machine clients, only hash of the key is stored
+type APIKey struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the beginning of the key, it tells keys apart in lists and logs
+	Prefix string `json:"prefix"`
+	// Key is the secret itself, it's returned only once when key is created
+	Key        string     `json:"key,omitempty"`
+	Hash       string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
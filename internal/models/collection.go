@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Collection is a curated ordered list of movies, private collections are visible only to editors
+type Collection struct {
+	ID          int    `json:"id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CoverURL    string `json:"cover_url"`
+	Public      bool   `json:"public"`
+	CreatedBy   string `json:"created_by"`
+	// MovieCount doesn't include movies in trash
+	MovieCount int       `json:"movie_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CollectionMovie is a member of collection
+type CollectionMovie struct {
+	MovieID     int       `json:"movie_id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	// Index is zero-based place of the movie in collection
+	Index   int       `json:"index"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// CollectionPlacement puts movie into collection at index, omitted index means the end of collection
+type CollectionPlacement struct {
+	MovieID int  `json:"movie_id"`
+	Index   *int `json:"index"`
+}
+
+// CollectionQuery describes filtering and pagination of collections list
+type CollectionQuery struct {
+	IncludePrivate bool
+	Limit          int
+	Offset         int
+}
+
+// CollectionList is a single page of collections list
+type CollectionList struct {
+	Collections []*Collection `json:"collections"`
+	Total       int           `json:"total"`
+	Limit       int           `json:"limit"`
+	Offset      int           `json:"offset"`
+	Next        string        `json:"next,omitempty"`
+}
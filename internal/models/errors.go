@@ -0,0 +1,17 @@
+package models
+
+import "errors"
+
+// Domain errors, repositories and services wrap them so handlers can choose response status
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+	ErrBadRequest = errors.New("bad request")
+
+	ErrUnauthorized = errors.New("authentication required")
+	ErrForbidden    = errors.New("forbidden")
+
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrPreconditionFailed   = errors.New("precondition failed")
+)
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Franchise is a series of movies in order chosen by editors
+type Franchise struct {
+	ID          int    `json:"id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Movies don't include movies in trash
+	Movies    []*FranchiseMovie `json:"movies"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// FranchiseMovie is a member of franchise
+type FranchiseMovie struct {
+	MovieID     int       `json:"movie_id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	// Position is zero-based place of the movie in franchise
+	Position int `json:"position"`
+}
+
+// FranchiseMembers replaces members of franchise, movies go in the order they are named
+type FranchiseMembers struct {
+	MovieIDs []int `json:"movie_ids"`
+}
+
+// FranchiseQuery describes pagination of franchises list
+type FranchiseQuery struct {
+	Limit  int
+	Offset int
+}
+
+// FranchiseList is a single page of franchises list
+type FranchiseList struct {
+	Franchises []*Franchise `json:"franchises"`
+	Total      int          `json:"total"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+	Next       string       `json:"next,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Genre is an entry of managed genres vocabulary, movies refer to genres by slug
+type Genre struct {
+	ID   int    `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
@@ -9,8 +9,34 @@ type Movie struct {
 	Title       string    `json:"title"`
 	Director    string    `json:"director"`
 	ReleaseDate time.Time `json:"release_date"`
+	// Genre is a legacy free-text genre, Genres holds slugs from genres vocabulary
 	Genre       string    `json:"genre"`
+	Genres      []string  `json:"genres"`
 	Description string    `json:"description"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// OriginalTitle is the title in original language, Runtime is length in minutes
+	OriginalTitle string `json:"original_title"`
+	Runtime       *int   `json:"runtime"`
+	// Countries of production are ISO 3166-1 alpha-2 codes, spoken Languages are ISO 639-1 codes
+	Countries []string `json:"countries"`
+	Languages []string `json:"languages"`
+	// Certifications map country codes to age ratings given there, e.g. "US": "PG-13"
+	Certifications map[string]string `json:"certifications"`
+	Budget         *Money            `json:"budget"`
+	BoxOffice      *Money            `json:"box_office"`
+	// Version grows with every update, it's used as ETag for optimistic concurrency
+	Version int `json:"version"`
+	// DeletedAt is set for movies moved to trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Rating aggregates scores of users, it's read-only
+	Rating RatingStats `json:"rating"`
+	// Tags are attached and detached with tag endpoints, they are read-only here
+	Tags []string `json:"tags"`
+}
+
+// Money is an amount in whole units of ISO 4217 currency
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
 }
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Fields which movies list can be sorted by
+const (
+	SortByTitle       = "title"
+	SortByReleaseDate = "release_date"
+	SortByCreatedAt   = "created_at"
+	SortByUpdatedAt   = "updated_at"
+	SortByRating      = "rating"
+)
+
+// MovieQuery describes filtering, sorting and pagination of movies list
+type MovieQuery struct {
+	Limit        int
+	Offset       int
+	Cursor       string
+	After        *MovieCursor
+	SortBy       string
+	SortDesc     bool
+	Genre        string
+	Director     string
+	ReleasedFrom *time.Time
+	ReleasedTo   *time.Time
+	// Tags keeps movies which have all of tags, or any of them when AnyTag is set
+	Tags   []string
+	AnyTag bool
+	// OriginalTitle matches part of original title, Country and Language are ISO codes
+	OriginalTitle string
+	Country       string
+	Language      string
+	// Certification keeps movies which are rated so in CertificationCountry
+	CertificationCountry string
+	Certification        string
+	// Bounds of runtime in minutes and money amounts, zero bound is not set.
+	// Budget and box office are compared in Currency only
+	RuntimeMin   int
+	RuntimeMax   int
+	Currency     string
+	BudgetMin    int
+	BudgetMax    int
+	BoxOfficeMin int
+	BoxOfficeMax int
+}
+
+// MovieCursor is a keyset position in movies list, sort key value with ID as a tiebreaker
+type MovieCursor struct {
+	Value string
+	ID    int
+}
+
+// MovieList is a single page of movies list
+type MovieList struct {
+	Movies     []*Movie `json:"movies"`
+	Total      int      `json:"total"`
+	Limit      int      `json:"limit"`
+	Offset     int      `json:"offset"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	Next       string   `json:"next,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Person describes people who took part in making movies
+type Person struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	BirthDate *time.Time `json:"birth_date,omitempty"`
+	Bio       string     `json:"bio"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Roles which person can have in a movie credit
+const (
+	CreditDirector = "director"
+	CreditWriter   = "writer"
+	CreditActor    = "actor"
+	CreditComposer = "composer"
+)
+
+// Credit links person to a movie with the role they had in it
+type Credit struct {
+	ID            int    `json:"id"`
+	MovieID       int    `json:"movie_id"`
+	MovieTitle    string `json:"movie_title,omitempty"`
+	PersonID      int    `json:"person_id"`
+	PersonName    string `json:"person_name,omitempty"`
+	Role          string `json:"role"`
+	CharacterName string `json:"character_name,omitempty"`
+}
+
+// PersonQuery describes filtering and pagination of people list
+type PersonQuery struct {
+	Name   string
+	Limit  int
+	Offset int
+}
+
+// PersonList is a single page of people list
+type PersonList struct {
+	People []*Person `json:"people"`
+	Total  int       `json:"total"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+	Next   string    `json:"next,omitempty"`
+}
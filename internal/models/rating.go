@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Bounds of rating score
+const (
+	MinRatingScore = 1
+	MaxRatingScore = 10
+)
+
+// Rating is a score which user gave to the movie, every user rates a movie once
+type Rating struct {
+	UserID    int       `json:"user_id"`
+	MovieID   int       `json:"movie_id"`
+	Score     int       `json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RatingStats aggregates ratings of the movie
+type RatingStats struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+	// Histogram holds number of ratings for every score, Histogram[0] is for score 1
+	Histogram []int `json:"histogram"`
+}
+
+// RatingResult is a rating of the current user with updated stats of the movie
+type RatingResult struct {
+	Rating *Rating      `json:"rating"`
+	Stats  *RatingStats `json:"stats"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Types of relations between movies
+const (
+	RelationSequel  = "sequel"
+	RelationPrequel = "prequel"
+	RelationRemake  = "remake"
+	RelationSpinOff = "spin_off"
+)
+
+// Relation tells that movie is sequel, prequel, remake or spin-off of related movie.
+// Sequels and prequels can't form cycles
+type Relation struct {
+	MovieID   int       `json:"movie_id"`
+	RelatedID int       `json:"related_id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RelatedMovie is a movie linked with another one by relation
+type RelatedMovie struct {
+	MovieID     int       `json:"movie_id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	// Type of relation and whether it points from the other movie to this one,
+	// the pair chooses group of RelatedMovies
+	Type    string `json:"-"`
+	Inverse bool   `json:"-"`
+}
+
+// Group returns name of RelatedMovies group of movie: "sequel_of" lists movies which the other one
+// is sequel of and "sequels" lists sequels of the other movie, other types are named the same way
+func (m *RelatedMovie) Group() string {
+	if m.Inverse {
+		return m.Type + "s"
+	}
+
+	return m.Type + "_of"
+}
+
+// RelatedMovies are movies related to movie grouped by relation and franchises the movie belongs to
+type RelatedMovies struct {
+	MovieID    int                        `json:"movie_id"`
+	Relations  map[string][]*RelatedMovie `json:"relations"`
+	Franchises []*Franchise               `json:"franchises"`
+}
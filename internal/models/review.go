@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Moderation states of reviews, only approved reviews are shown to everyone
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Fields which reviews list can be sorted by
+const (
+	ReviewSortByCreatedAt = "created_at"
+	ReviewSortByHelpful   = "helpful"
+)
+
+// Review is a text review of the movie, every user reviews a movie once
+type Review struct {
+	ID      int    `json:"id"`
+	MovieID int    `json:"movie_id"`
+	UserID  int    `json:"user_id"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	// Status is one of Review* states, review goes back to pending when its author edits it
+	Status         string     `json:"status"`
+	ModeratedBy    string     `json:"moderated_by,omitempty"`
+	ModerationNote string     `json:"moderation_note,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	HelpfulCount   int        `json:"helpful_count"`
+	UnhelpfulCount int        `json:"unhelpful_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ReviewQuery describes filtering, sorting and pagination of reviews list,
+// zero MovieID lists reviews of all movies
+type ReviewQuery struct {
+	MovieID  int
+	Status   string
+	SortBy   string
+	SortDesc bool
+	Limit    int
+	Offset   int
+}
+
+// ReviewList is a single page of reviews list
+type ReviewList struct {
+	Reviews []*Review `json:"reviews"`
+	Total   int       `json:"total"`
+	Limit   int       `json:"limit"`
+	Offset  int       `json:"offset"`
+	Next    string    `json:"next,omitempty"`
+}
+
+// Moderation is a decision of moderator about review
+type Moderation struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// ReviewVote tells whether user found review helpful
+type ReviewVote struct {
+	Helpful bool `json:"helpful"`
+}
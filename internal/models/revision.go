@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Actions which create movie revisions
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
+
+// Revision is the state of a movie right after one of its changes
+type Revision struct {
+	ID        int       `json:"id"`
+	MovieID   int       `json:"movie_id"`
+	Version   int       `json:"version"`
+	Action    string    `json:"action"`
+	ChangedBy string    `json:"changed_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Changes lists fields which differ from the previous revision
+	Changes []FieldChange `json:"changes"`
+	// Snapshot is the whole movie, it's not a part of history response
+	Snapshot *Movie `json:"-"`
+}
+
+// FieldChange describes change of a single movie field
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Roles of API callers, every role is allowed to do everything the previous one can
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// RoleAssignment grants role to the subject of access tokens
+type RoleAssignment struct {
+	Subject   string    `json:"subject"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// SearchQuery describes full-text search request over movies
+type SearchQuery struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// SearchResult is a movie matched by full-text search
+type SearchResult struct {
+	Movie   *Movie  `json:"movie"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// SearchResults is a single page of search results ordered by rank
+type SearchResults struct {
+	Results []*SearchResult `json:"results"`
+	Total   int             `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+	Next    string          `json:"next,omitempty"`
+}
+
+// Suggestion is a movie which title or director is similar to typed text
+type Suggestion struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Director    string    `json:"director"`
+	ReleaseDate time.Time `json:"release_date"`
+	Score       float64   `json:"score"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// Tag is a free-form label of movies, Count is a number of movies which have it and aren't in trash
+type Tag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// MovieTags is a set of tag names attached to or detached from movie
+type MovieTags struct {
+	Tags []string `json:"tags"`
+}
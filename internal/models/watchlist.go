@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// WatchlistItem is a movie which user wants to watch
+type WatchlistItem struct {
+	MovieID     int       `json:"movie_id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	// Position orders items of the list, smaller go first
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// HistoryEntry is a movie which user has watched
+type HistoryEntry struct {
+	MovieID     int       `json:"movie_id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	Position    int       `json:"position"`
+	// WatchedAt is optional date of watching, RewatchCount tells how many times movie was watched again
+	WatchedAt    *time.Time `json:"watched_at,omitempty"`
+	RewatchCount int        `json:"rewatch_count"`
+	AddedAt      time.Time  `json:"added_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ListOrder is a new order of watchlist or history, it names every movie of the list
+type ListOrder struct {
+	MovieIDs []int `json:"movie_ids"`
+}
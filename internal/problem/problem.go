@@ -0,0 +1,60 @@
+// Package problem provides RFC 7807 problem details which are used as body of every error response
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentType of problem details responses
+const ContentType = "application/problem+json"
+
+// Types of problems, they are relative URIs and stable for clients
+const (
+	TypeBadRequest = "/problems/bad-request"
+	TypeNotFound   = "/problems/not-found"
+	TypeConflict   = "/problems/conflict"
+	TypeValidation = "/problems/validation"
+	TypeInternal   = "/problems/internal"
+
+	TypeUnauthorized = "/problems/unauthorized"
+	TypeForbidden    = "/problems/forbidden"
+
+	TypeUnsupportedMediaType = "/problems/unsupported-media-type"
+	TypePreconditionFailed   = "/problems/precondition-failed"
+)
+
+// InvalidParam describes single invalid parameter of request
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// Problem is the body of error response
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	RequestID     string         `json:"request_id,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// New creates problem of given type and status, title is the status text
+func New(typ string, status int, detail string) *Problem {
+	return &Problem{
+		Type:   typ,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Write answers with problem as application/problem+json
+func Write(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,224 @@
+// Package apikey provides communication application with db for API keys
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+	"github.com/lib/pq"
+)
+
+// Repository interface describes functions which object must implements to store API keys
+type Repository interface {
+	Create(context.Context, *models.APIKey) error
+	List(context.Context) ([]*models.APIKey, error)
+	Revoke(ctx context.Context, id int) error
+	// Use returns active key with hash and sets its last used time,
+	// the time is updated at most once a minute
+	Use(ctx context.Context, hash string) (*models.APIKey, error)
+}
+
+// lastUsedPrecision is how stale last used time of key may get, updating it on every request
+// makes concurrent requests with one key queue up on its row
+const lastUsedPrecision = time.Minute
+
+type apiKeyPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewAPIKeyPostgresRepo creates new instance of apiKeyPostgresRepo
+func NewAPIKeyPostgresRepo(db *sql.DB) Repository {
+	return &apiKeyPostgresRepo{db}
+}
+
+const apiKeyColumns = `
+	id,
+	name,
+	prefix,
+	scopes,
+	created_by,
+	expires_at,
+	last_used_at,
+	revoked_at,
+	created_at
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var (
+		key                            models.APIKey
+		expiresAt, lastUsedAt, revoked sql.NullTime
+	)
+
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		pq.Array(&key.Scopes),
+		&key.CreatedBy,
+		&expiresAt,
+		&lastUsedAt,
+		&revoked,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revoked.Valid {
+		key.RevokedAt = &revoked.Time
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyPostgresRepo) Create(ctx context.Context, key *models.APIKey) error {
+	query := `
+		INSERT INTO
+			api_keys (
+				name,
+				prefix,
+				key_hash,
+				scopes,
+				created_by,
+				expires_at
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		key.Name,
+		key.Prefix,
+		key.Hash,
+		pq.Array(key.Scopes),
+		key.CreatedBy,
+		key.ExpiresAt,
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *apiKeyPostgresRepo) List(ctx context.Context) ([]*models.APIKey, error) {
+	query := `
+		SELECT` + apiKeyColumns + `
+		FROM
+			api_keys
+		ORDER BY
+			id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %v", err)
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %v", err)
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyPostgresRepo) Revoke(ctx context.Context, id int) error {
+	query := `
+		UPDATE
+			api_keys
+		SET revoked_at = NOW()
+		WHERE
+			id = $1
+			AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %v", err)
+	}
+
+	return repository.CheckAffected(result, "active api key", id)
+}
+
+func (r *apiKeyPostgresRepo) Use(ctx context.Context, hash string) (*models.APIKey, error) {
+	query := `
+		SELECT` + apiKeyColumns + `
+		FROM
+			api_keys
+		WHERE
+			key_hash = $1
+			AND revoked_at IS NULL
+			AND (
+				expires_at IS NULL
+				OR expires_at > NOW()
+			)
+	`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, hash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("api key", "with given hash")
+		}
+		return nil, fmt.Errorf("failed to use api key: %v", err)
+	}
+
+	if key.LastUsedAt != nil && time.Since(*key.LastUsedAt) < lastUsedPrecision {
+		return key, nil
+	}
+
+	// concurrent requests with the key don't wait for each other to write the same minute,
+	// the one which finds time already updated changes nothing
+	var lastUsedAt time.Time
+	err = r.db.QueryRowContext(ctx, `
+		UPDATE
+			api_keys
+		SET last_used_at = NOW()
+		WHERE
+			id = $1
+			AND (
+				last_used_at IS NULL
+				OR last_used_at < NOW() - INTERVAL '1 minute'
+			)
+		RETURNING
+			last_used_at
+	`, key.ID).Scan(&lastUsedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return key, nil
+		}
+		return nil, fmt.Errorf("failed to update api key last used time: %v", err)
+	}
+
+	key.LastUsedAt = &lastUsedAt
+
+	return key, nil
+}
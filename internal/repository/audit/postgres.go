@@ -0,0 +1,202 @@
+// Package audit provides communication application with db for audit log
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+// Repository interface describes functions which object must implements to store audit log,
+// entries can only be added
+type Repository interface {
+	Create(context.Context, *models.AuditEntry) error
+	List(context.Context, *models.AuditQuery) ([]*models.AuditEntry, int, error)
+}
+
+type auditPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewAuditPostgresRepo creates new instance of auditPostgresRepo
+func NewAuditPostgresRepo(db *sql.DB) Repository {
+	return &auditPostgresRepo{db}
+}
+
+func (r *auditPostgresRepo) Create(ctx context.Context, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO
+			audit_log (
+				actor,
+				method,
+				path,
+				status,
+				request_id,
+				source_ip,
+				entity_type,
+				entity_id,
+				before,
+				after
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		entry.Actor,
+		entry.Method,
+		entry.Path,
+		entry.Status,
+		entry.RequestID,
+		entry.SourceIP,
+		entry.EntityType,
+		entry.EntityID,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+	).Scan(&entry.ID, &entry.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %v", err)
+	}
+
+	return nil
+}
+
+func (r *auditPostgresRepo) List(ctx context.Context, q *models.AuditQuery) ([]*models.AuditEntry, int, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	addCondition := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if q.Actor != "" {
+		addCondition("actor = $%d", q.Actor)
+	}
+
+	if q.Method != "" {
+		addCondition("method = UPPER($%d)", q.Method)
+	}
+
+	if q.EntityType != "" {
+		addCondition("entity_type = $%d", q.EntityType)
+	}
+
+	if q.EntityID != "" {
+		addCondition("entity_id = $%d", q.EntityID)
+	}
+
+	if q.From != nil {
+		addCondition("created_at >= $%d", *q.From)
+	}
+
+	if q.To != nil {
+		addCondition("created_at <= $%d", *q.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := `
+		SELECT
+			COUNT(*)
+		FROM
+			audit_log
+	` + where
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %v", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			actor,
+			method,
+			path,
+			status,
+			request_id,
+			source_ip,
+			entity_type,
+			entity_id,
+			before,
+			after,
+			created_at
+		FROM
+			audit_log
+		%s
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT $%d
+		OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, q.Limit, q.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %v", err)
+	}
+
+	defer rows.Close()
+
+	entries := make([]*models.AuditEntry, 0, q.Limit)
+
+	for rows.Next() {
+		var (
+			entry models.AuditEntry
+			// RawMessage can't hold NULL, so snapshots are scanned as plain bytes
+			before, after []byte
+		)
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.Method,
+			&entry.Path,
+			&entry.Status,
+			&entry.RequestID,
+			&entry.SourceIP,
+			&entry.EntityType,
+			&entry.EntityID,
+			&before,
+			&after,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %v", err)
+		}
+
+		entry.Before, entry.After = before, after
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %v", err)
+	}
+
+	return entries, total, nil
+}
+
+// nullJSON stores missing snapshots as NULL instead of empty string which is not valid JSONB
+func nullJSON(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return string(b)
+}
@@ -0,0 +1,21 @@
+package collection
+
+// positionGap is the distance between positions of neighbours after appending or renumbering
+const positionGap = 1024
+
+// positionBetween returns position for movie placed between neighbours, nil neighbour means
+// the edge of collection. ok is false when there is no room left and collection must be renumbered
+func positionBetween(prev, next *int64) (position int64, ok bool) {
+	switch {
+	case prev == nil && next == nil:
+		return positionGap, true
+	case next == nil:
+		return *prev + positionGap, true
+	case prev == nil:
+		return *next - positionGap, true
+	case *next-*prev < 2:
+		return 0, false
+	default:
+		return *prev + (*next-*prev)/2, true
+	}
+}
@@ -0,0 +1,36 @@
+package collection
+
+import "testing"
+
+func TestPositionBetween(t *testing.T) {
+	p := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		prev, next *int64
+		expected   int64
+		ok         bool
+	}{
+		{nil, nil, positionGap, true},
+		{p(2048), nil, 2048 + positionGap, true},
+		{nil, p(1024), 0, true},
+		{nil, p(0), -positionGap, true},
+		{p(1024), p(2048), 1536, true},
+		{p(1024), p(1026), 1025, true},
+		{p(1024), p(1025), 0, false},
+		{p(1024), p(1024), 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := positionBetween(tt.prev, tt.next)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("positionBetween(%v, %v) = %d, %v, expected %d, %v", deref(tt.prev), deref(tt.next), got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func deref(v *int64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
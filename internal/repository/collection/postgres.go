@@ -0,0 +1,623 @@
+// Package collection provides communication application with db for curated collections of movies
+package collection
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+// Repository interface describes functions which object must implements to store collections.
+// Members which are in trash are hidden and don't count in indexes of other members
+type Repository interface {
+	Create(context.Context, *models.Collection) error
+	GetBySlug(context.Context, string) (*models.Collection, error)
+	Update(ctx context.Context, slug string, collection *models.Collection) (*models.Collection, error)
+	Delete(context.Context, string) error
+	List(context.Context, *models.CollectionQuery) ([]*models.Collection, int, error)
+	// ListByMovie returns collections which movie belongs to
+	ListByMovie(ctx context.Context, movieID int, includePrivate bool) ([]*models.Collection, error)
+	ListMovies(ctx context.Context, collectionID int) ([]*models.CollectionMovie, error)
+	// AddMovie puts movie which isn't in trash into collection, it fails with models.ErrConflict
+	// when movie is already there
+	AddMovie(ctx context.Context, collectionID int, placement *models.CollectionPlacement) (*models.CollectionMovie, error)
+	MoveMovie(ctx context.Context, collectionID, movieID, index int) (*models.CollectionMovie, error)
+	RemoveMovie(ctx context.Context, collectionID, movieID int) error
+}
+
+type collectionPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewCollectionPostgresRepo creates new instance of collectionPostgresRepo
+func NewCollectionPostgresRepo(db *sql.DB) Repository {
+	return &collectionPostgresRepo{db}
+}
+
+const collectionColumns = `
+	id,
+	slug,
+	name,
+	description,
+	cover_url,
+	is_public,
+	created_by,
+	(
+		SELECT
+			COUNT(*)
+		FROM
+			collection_movies cm
+			JOIN movies m ON m.id = cm.movie_id
+		WHERE
+			cm.collection_id = collections.id
+			AND m.deleted_at IS NULL
+	),
+	created_at,
+	updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCollection(row rowScanner) (*models.Collection, error) {
+	var c models.Collection
+
+	err := row.Scan(
+		&c.ID,
+		&c.Slug,
+		&c.Name,
+		&c.Description,
+		&c.CoverURL,
+		&c.Public,
+		&c.CreatedBy,
+		&c.MovieCount,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (r *collectionPostgresRepo) Create(ctx context.Context, c *models.Collection) error {
+	query := `
+		INSERT INTO
+			collections (
+				slug,
+				name,
+				description,
+				cover_url,
+				is_public,
+				created_by
+			)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		RETURNING
+			id,
+			created_at,
+			updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, c.Slug, c.Name, c.Description, c.CoverURL, c.Public, c.CreatedBy).Scan(
+		&c.ID,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *collectionPostgresRepo) GetBySlug(ctx context.Context, slug string) (*models.Collection, error) {
+	query := `
+		SELECT` + collectionColumns + `
+		FROM
+			collections
+		WHERE
+			slug = $1
+	`
+
+	c, err := scanCollection(r.db.QueryRowContext(ctx, query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("collection", slug)
+		}
+		return nil, fmt.Errorf("failed to get collection: %v", err)
+	}
+
+	return c, nil
+}
+
+func (r *collectionPostgresRepo) Update(ctx context.Context, slug string, c *models.Collection) (*models.Collection, error) {
+	query := `
+		UPDATE
+			collections
+		SET
+			slug = $1,
+			name = $2,
+			description = $3,
+			cover_url = $4,
+			is_public = $5
+		WHERE
+			slug = $6
+		RETURNING` + collectionColumns
+
+	updated, err := scanCollection(r.db.QueryRowContext(ctx, query, c.Slug, c.Name, c.Description, c.CoverURL, c.Public, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("collection", slug)
+		}
+		return nil, fmt.Errorf("failed to update collection: %w", repository.Error(err))
+	}
+
+	return updated, nil
+}
+
+func (r *collectionPostgresRepo) Delete(ctx context.Context, slug string) error {
+	query := `
+		DELETE FROM collections
+		WHERE
+			slug = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, slug)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %v", err)
+	}
+
+	return repository.CheckAffected(result, "collection", slug)
+}
+
+func (r *collectionPostgresRepo) List(ctx context.Context, q *models.CollectionQuery) ([]*models.Collection, int, error) {
+	where := ""
+	if !q.IncludePrivate {
+		where = "WHERE is_public"
+	}
+
+	countQuery := `
+		SELECT
+			COUNT(*)
+		FROM
+			collections
+	` + where
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count collections: %v", err)
+	}
+
+	query := `
+		SELECT` + collectionColumns + `
+		FROM
+			collections
+		` + where + `
+		ORDER BY
+			name,
+			id
+		LIMIT $1
+		OFFSET $2
+	`
+
+	collections, err := r.listCollections(ctx, query, q.Limit, q.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return collections, total, nil
+}
+
+func (r *collectionPostgresRepo) ListByMovie(ctx context.Context, movieID int, includePrivate bool) ([]*models.Collection, error) {
+	query := `
+		SELECT` + collectionColumns + `
+		FROM
+			collections
+		WHERE
+			(is_public OR $2)
+			AND id IN (
+				SELECT
+					collection_id
+				FROM
+					collection_movies
+				WHERE
+					movie_id = $1
+			)
+		ORDER BY
+			name,
+			id
+	`
+
+	return r.listCollections(ctx, query, movieID, includePrivate)
+}
+
+func (r *collectionPostgresRepo) listCollections(ctx context.Context, query string, args ...any) ([]*models.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %v", err)
+	}
+	defer rows.Close()
+
+	collections := []*models.Collection{}
+	for rows.Next() {
+		c, err := scanCollection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %v", err)
+		}
+		collections = append(collections, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list collections: %v", err)
+	}
+
+	return collections, nil
+}
+
+// collectionMoviesQuery numbers visible members of collection in order of their positions
+const collectionMoviesQuery = `
+	SELECT
+		cm.movie_id,
+		m.title,
+		m.release_date,
+		ROW_NUMBER() OVER (
+			ORDER BY
+				cm.position,
+				cm.movie_id
+		) - 1 AS idx,
+		cm.added_at
+	FROM
+		collection_movies cm
+		JOIN movies m ON m.id = cm.movie_id
+	WHERE
+		cm.collection_id = $1
+		AND m.deleted_at IS NULL
+`
+
+func scanCollectionMovie(row rowScanner) (*models.CollectionMovie, error) {
+	var item models.CollectionMovie
+
+	if err := row.Scan(&item.MovieID, &item.Title, &item.ReleaseDate, &item.Index, &item.AddedAt); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *collectionPostgresRepo) ListMovies(ctx context.Context, collectionID int) ([]*models.CollectionMovie, error) {
+	query := collectionMoviesQuery + `
+		ORDER BY
+			idx
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection movies: %v", err)
+	}
+	defer rows.Close()
+
+	items := []*models.CollectionMovie{}
+	for rows.Next() {
+		item, err := scanCollectionMovie(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection movie: %v", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list collection movies: %v", err)
+	}
+
+	return items, nil
+}
+
+func (r *collectionPostgresRepo) AddMovie(ctx context.Context, collectionID int, placement *models.CollectionPlacement) (*models.CollectionMovie, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockCollection(ctx, tx, collectionID); err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					movies
+				WHERE
+					id = $1
+					AND deleted_at IS NULL
+			)
+	`, placement.MovieID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check movie: %v", err)
+	}
+
+	if !exists {
+		return nil, repository.NotFound("movie", placement.MovieID)
+	}
+
+	position, err := place(ctx, tx, collectionID, placement.MovieID, placement.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO
+			collection_movies (
+				collection_id,
+				movie_id,
+				position
+			)
+		VALUES
+			($1, $2, $3)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, collectionID, placement.MovieID, position); err != nil {
+		return nil, fmt.Errorf("failed to add movie to collection: %w", repository.Error(err))
+	}
+
+	item, err := getCollectionMovie(ctx, tx, collectionID, placement.MovieID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit adding movie to collection: %v", err)
+	}
+
+	return item, nil
+}
+
+func (r *collectionPostgresRepo) MoveMovie(ctx context.Context, collectionID, movieID, index int) (*models.CollectionMovie, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockCollection(ctx, tx, collectionID); err != nil {
+		return nil, err
+	}
+
+	if _, err := getCollectionMovie(ctx, tx, collectionID, movieID); err != nil {
+		return nil, err
+	}
+
+	position, err := place(ctx, tx, collectionID, movieID, &index)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE
+			collection_movies
+		SET
+			position = $3
+		WHERE
+			collection_id = $1
+			AND movie_id = $2
+	`
+
+	if _, err := tx.ExecContext(ctx, query, collectionID, movieID, position); err != nil {
+		return nil, fmt.Errorf("failed to move movie in collection: %v", err)
+	}
+
+	item, err := getCollectionMovie(ctx, tx, collectionID, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit moving movie in collection: %v", err)
+	}
+
+	return item, nil
+}
+
+func (r *collectionPostgresRepo) RemoveMovie(ctx context.Context, collectionID, movieID int) error {
+	query := `
+		DELETE FROM collection_movies
+		WHERE
+			collection_id = $1
+			AND movie_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, collectionID, movieID)
+	if err != nil {
+		return fmt.Errorf("failed to remove movie from collection: %v", err)
+	}
+
+	return repository.CheckAffected(result, "movie in collection", movieID)
+}
+
+// lockCollection serializes changes of member positions
+func lockCollection(ctx context.Context, tx *sql.Tx, collectionID int) error {
+	var id int
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			id
+		FROM
+			collections
+		WHERE
+			id = $1
+		FOR UPDATE
+	`, collectionID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.NotFound("collection", collectionID)
+		}
+		return fmt.Errorf("failed to lock collection: %v", err)
+	}
+
+	return nil
+}
+
+func getCollectionMovie(ctx context.Context, db repository.DBTX, collectionID, movieID int) (*models.CollectionMovie, error) {
+	query := `
+		SELECT
+			*
+		FROM
+			(` + collectionMoviesQuery + `) members
+		WHERE
+			movie_id = $2
+	`
+
+	item, err := scanCollectionMovie(db.QueryRowContext(ctx, query, collectionID, movieID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("movie in collection", movieID)
+		}
+		return nil, fmt.Errorf("failed to get collection movie: %v", err)
+	}
+
+	return item, nil
+}
+
+// place returns position for movie at index among other visible members, nil index means the end.
+// Collection is renumbered when neighbours have no room left between them
+func place(ctx context.Context, db repository.DBTX, collectionID, movieID int, index *int) (int64, error) {
+	prev, next, err := neighbours(ctx, db, collectionID, movieID, index)
+	if err != nil {
+		return 0, err
+	}
+
+	if position, ok := positionBetween(prev, next); ok {
+		return position, nil
+	}
+
+	if err := renumber(ctx, db, collectionID); err != nil {
+		return 0, err
+	}
+
+	if prev, next, err = neighbours(ctx, db, collectionID, movieID, index); err != nil {
+		return 0, err
+	}
+
+	position, _ := positionBetween(prev, next)
+	return position, nil
+}
+
+// neighbours returns positions of members which will be just before and after the movie placed at index
+func neighbours(ctx context.Context, db repository.DBTX, collectionID, movieID int, index *int) (prev, next *int64, err error) {
+	if index != nil {
+		query := `
+			SELECT
+				cm.position
+			FROM
+				collection_movies cm
+				JOIN movies m ON m.id = cm.movie_id
+			WHERE
+				cm.collection_id = $1
+				AND cm.movie_id <> $2
+				AND m.deleted_at IS NULL
+			ORDER BY
+				cm.position,
+				cm.movie_id
+			LIMIT $3
+			OFFSET $4
+		`
+
+		limit, offset := 2, *index-1
+		if *index == 0 {
+			limit, offset = 1, 0
+		}
+
+		rows, err := db.QueryContext(ctx, query, collectionID, movieID, limit, offset)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get neighbours in collection: %v", err)
+		}
+		defer rows.Close()
+
+		var positions []int64
+		for rows.Next() {
+			var position int64
+			if err := rows.Scan(&position); err != nil {
+				return nil, nil, fmt.Errorf("failed to scan position: %v", err)
+			}
+			positions = append(positions, position)
+		}
+
+		if err := rows.Err(); err != nil {
+			return nil, nil, fmt.Errorf("failed to get neighbours in collection: %v", err)
+		}
+
+		switch {
+		case *index == 0 && len(positions) == 1:
+			return nil, &positions[0], nil
+		case *index == 0:
+			return nil, nil, nil
+		case len(positions) == 2:
+			return &positions[0], &positions[1], nil
+		}
+
+		// index beyond the end appends movie like nil index does
+	}
+
+	var last sql.NullInt64
+
+	err = db.QueryRowContext(ctx, `
+		SELECT
+			MAX(position)
+		FROM
+			collection_movies
+		WHERE
+			collection_id = $1
+			AND movie_id <> $2
+	`, collectionID, movieID).Scan(&last)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get last position in collection: %v", err)
+	}
+
+	if !last.Valid {
+		return nil, nil, nil
+	}
+
+	return &last.Int64, nil, nil
+}
+
+// renumber spreads positions of all members, hidden ones included, positionGap apart
+func renumber(ctx context.Context, db repository.DBTX, collectionID int) error {
+	query := `
+		UPDATE
+			collection_movies cm
+		SET
+			position = o.n * $2
+		FROM
+			(
+				SELECT
+					movie_id,
+					ROW_NUMBER() OVER (
+						ORDER BY
+							position,
+							movie_id
+					) AS n
+				FROM
+					collection_movies
+				WHERE
+					collection_id = $1
+			) o
+		WHERE
+			cm.collection_id = $1
+			AND cm.movie_id = o.movie_id
+	`
+
+	if _, err := db.ExecContext(ctx, query, collectionID, positionGap); err != nil {
+		return fmt.Errorf("failed to renumber collection: %v", err)
+	}
+
+	return nil
+}
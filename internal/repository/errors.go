@@ -0,0 +1,146 @@
+// Package repository provides helpers shared by db repositories
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/lib/pq"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	codeForeignKeyViolation = "23503"
+	codeUniqueViolation     = "23505"
+	codeCheckViolation      = "23514"
+)
+
+// constraintDetails describe violated constraints to clients, names are the ones postgres
+// generates or migrations give
+var constraintDetails = map[string]string{
+	"genres_slug_key":                   "genre with this slug already exists",
+	"genres_slug_check":                 "slug must be lowercase letters and digits separated by dashes",
+	"movie_genres_genre_id_fkey":        "genre doesn't exist or is still used by movies",
+	"idx_movie_credits_unique":          "person already has this credit in the movie",
+	"movie_credits_role_check":          "role must be one of director, writer, actor, composer",
+	"movie_credits_person_id_fkey":      "person doesn't exist",
+	"movie_revisions_action_check":      "revision action is unknown",
+	"user_roles_role_check":             "role must be one of viewer, editor, admin",
+	"api_keys_key_hash_key":             "api key already exists",
+	"api_keys_scopes_check":             "at least one scope is required",
+	"idx_users_email":                   "user with this email already exists",
+	"refresh_tokens_token_hash_key":     "refresh token already exists",
+	"movie_ratings_score_check":         "score must be between 1 and 10",
+	"reviews_movie_id_user_id_key":      "movie is already reviewed by the user",
+	"reviews_status_check":              "status must be one of pending, approved, rejected",
+	"review_votes_pkey":                 "review is already voted by the user",
+	"watch_history_rewatch_count_check": "rewatch count can't be negative",
+	"collections_slug_key":              "collection with this slug already exists",
+	"collections_slug_check":            "slug must be lowercase letters and digits separated by dashes",
+	"collection_movies_pkey":            "movie is already in the collection",
+	"movie_relations_pkey":              "movies are already related",
+	"movie_relations_check":             "movie can't be related to itself",
+	"movie_relations_type_check":        "relation type must be one of sequel, prequel, remake, spin_off",
+	"franchises_slug_key":               "franchise with this slug already exists",
+	"franchises_slug_check":             "slug must be lowercase letters and digits separated by dashes",
+	"franchise_movies_pkey":             "movie is already in the franchise",
+	"tags_name_key":                     "tag already exists",
+	"tags_name_check":                   "tag must be lowercase and not blank",
+	"movies_runtime_check":              "runtime must be positive",
+	"movies_certifications_check":       "certifications must be an object",
+	"movies_budget_check":               "budget can't be negative",
+	"movies_box_office_check":           "box office can't be negative",
+	"movies_budget_currency_check":      "budget amount and currency must be set together",
+	"movies_box_office_currency_check":  "box office amount and currency must be set together",
+}
+
+// ConstraintError is a violated constraint. Error keeps message of the driver for server logs,
+// while PublicDetail is a fixed description which is safe to show to clients
+type ConstraintError struct {
+	kind   error
+	detail string
+	cause  *pq.Error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%v: %s", e.kind, e.cause.Message)
+}
+
+// Unwrap makes ConstraintError match both domain error and driver error
+func (e *ConstraintError) Unwrap() []error {
+	return []error{e.kind, e.cause}
+}
+
+// PublicDetail describes violation without names of tables, columns and constraints
+func (e *ConstraintError) PublicDetail() string {
+	return e.kind.Error() + ": " + e.detail
+}
+
+// Error wraps constraint violations into *ConstraintError matching domain errors of models package,
+// other errors are returned as is
+func Error(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	var kind error
+
+	switch pqErr.Code {
+	case codeUniqueViolation, codeForeignKeyViolation:
+		kind = models.ErrConflict
+	case codeCheckViolation:
+		kind = models.ErrValidation
+	default:
+		return err
+	}
+
+	return &ConstraintError{kind: kind, detail: constraintDetail(pqErr), cause: pqErr}
+}
+
+// constraintDetail describes known constraints specifically and the rest by kind of violation
+func constraintDetail(pqErr *pq.Error) string {
+	if detail, ok := constraintDetails[pqErr.Constraint]; ok {
+		return detail
+	}
+
+	switch {
+	case pqErr.Code == codeUniqueViolation:
+		return "resource already exists"
+	case strings.HasSuffix(pqErr.Constraint, "_movie_id_fkey"):
+		return "movie doesn't exist"
+	case strings.HasSuffix(pqErr.Constraint, "_user_id_fkey"):
+		return "user doesn't exist"
+	case pqErr.Code == codeForeignKeyViolation:
+		return "referenced resource doesn't exist or is still in use"
+	default:
+		return "value is not allowed"
+	}
+}
+
+// IsForeignKeyViolation reports whether err is caused by reference to a missing row
+func IsForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == codeForeignKeyViolation
+}
+
+// NotFound returns models.ErrNotFound describing missing entity
+func NotFound(entity string, key any) error {
+	return fmt.Errorf("%s %v: %w", entity, key, models.ErrNotFound)
+}
+
+// CheckAffected returns not found error when statement affected no rows
+func CheckAffected(result interface{ RowsAffected() (int64, error) }, entity string, key any) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if n == 0 {
+		return NotFound(entity, key)
+	}
+
+	return nil
+}
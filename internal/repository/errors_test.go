@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/lib/pq"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		err    *pq.Error
+		kind   error
+		detail string
+	}{
+		{
+			&pq.Error{Code: codeUniqueViolation, Constraint: "genres_slug_key", Message: `duplicate key value violates unique constraint "genres_slug_key"`},
+			models.ErrConflict,
+			"conflict: genre with this slug already exists",
+		},
+		{
+			&pq.Error{Code: codeForeignKeyViolation, Constraint: "watchlist_items_movie_id_fkey", Message: `insert or update on table "watchlist_items" violates foreign key constraint`},
+			models.ErrConflict,
+			"conflict: movie doesn't exist",
+		},
+		{
+			&pq.Error{Code: codeCheckViolation, Constraint: "movies_some_new_check", Message: `new row for relation "movies" violates check constraint "movies_some_new_check"`},
+			models.ErrValidation,
+			"validation failed: value is not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		err := fmt.Errorf("failed to write: %w", Error(tt.err))
+
+		if !errors.Is(err, tt.kind) {
+			t.Errorf("Expected %q to match %v", err, tt.kind)
+		}
+
+		var cerr *ConstraintError
+		if !errors.As(err, &cerr) {
+			t.Fatalf("Expected ConstraintError, got %v", err)
+		}
+
+		if cerr.PublicDetail() != tt.detail {
+			t.Errorf("Expected detail %q, got %q", tt.detail, cerr.PublicDetail())
+		}
+
+		if strings.Contains(cerr.PublicDetail(), tt.err.Constraint) || !strings.Contains(err.Error(), tt.err.Message) {
+			t.Errorf("Expected driver message to be logged only, got detail %q and error %q", cerr.PublicDetail(), err)
+		}
+	}
+
+	if !IsForeignKeyViolation(Error(&pq.Error{Code: codeForeignKeyViolation})) {
+		t.Error("Expected wrapped driver error to be reported as foreign key violation")
+	}
+
+	other := errors.New("connection refused")
+	if Error(other) != other {
+		t.Error("Expected other errors to be returned as is")
+	}
+}
@@ -0,0 +1,389 @@
+// Package franchise provides communication application with db for franchises of movies
+package franchise
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+	"github.com/lib/pq"
+)
+
+// Repository interface describes functions which object must implements to store franchises.
+// Franchises are returned with their members, movies in trash are hidden
+type Repository interface {
+	Create(context.Context, *models.Franchise) error
+	GetBySlug(context.Context, string) (*models.Franchise, error)
+	Update(ctx context.Context, slug string, franchise *models.Franchise) (*models.Franchise, error)
+	Delete(context.Context, string) error
+	List(context.Context, *models.FranchiseQuery) ([]*models.Franchise, int, error)
+	// ListByMovie returns franchises which movie belongs to
+	ListByMovie(ctx context.Context, movieID int) ([]*models.Franchise, error)
+	// SetMovies replaces visible members of franchise with movies in given order, members in trash
+	// are kept. It fails with models.ErrNotFound when some movie doesn't exist or is in trash
+	SetMovies(ctx context.Context, franchiseID int, movieIDs []int) ([]*models.FranchiseMovie, error)
+}
+
+type franchisePostgresRepo struct {
+	db *sql.DB
+}
+
+// NewFranchisePostgresRepo creates new instance of franchisePostgresRepo
+func NewFranchisePostgresRepo(db *sql.DB) Repository {
+	return &franchisePostgresRepo{db}
+}
+
+const franchiseColumns = `
+	id,
+	slug,
+	name,
+	description,
+	created_at,
+	updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFranchise(row rowScanner) (*models.Franchise, error) {
+	f := models.Franchise{Movies: []*models.FranchiseMovie{}}
+
+	if err := row.Scan(&f.ID, &f.Slug, &f.Name, &f.Description, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func (r *franchisePostgresRepo) Create(ctx context.Context, f *models.Franchise) error {
+	query := `
+		INSERT INTO
+			franchises (
+				slug,
+				name,
+				description
+			)
+		VALUES
+			($1, $2, $3)
+		RETURNING
+			id,
+			created_at,
+			updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, f.Slug, f.Name, f.Description).Scan(
+		&f.ID,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create franchise: %w", repository.Error(err))
+	}
+
+	f.Movies = []*models.FranchiseMovie{}
+
+	return nil
+}
+
+func (r *franchisePostgresRepo) GetBySlug(ctx context.Context, slug string) (*models.Franchise, error) {
+	query := `
+		SELECT` + franchiseColumns + `
+		FROM
+			franchises
+		WHERE
+			slug = $1
+	`
+
+	f, err := scanFranchise(r.db.QueryRowContext(ctx, query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("franchise", slug)
+		}
+		return nil, fmt.Errorf("failed to get franchise: %v", err)
+	}
+
+	if err := loadMovies(ctx, r.db, f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (r *franchisePostgresRepo) Update(ctx context.Context, slug string, f *models.Franchise) (*models.Franchise, error) {
+	query := `
+		UPDATE
+			franchises
+		SET
+			slug = $1,
+			name = $2,
+			description = $3
+		WHERE
+			slug = $4
+		RETURNING` + franchiseColumns
+
+	updated, err := scanFranchise(r.db.QueryRowContext(ctx, query, f.Slug, f.Name, f.Description, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("franchise", slug)
+		}
+		return nil, fmt.Errorf("failed to update franchise: %w", repository.Error(err))
+	}
+
+	if err := loadMovies(ctx, r.db, updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (r *franchisePostgresRepo) Delete(ctx context.Context, slug string) error {
+	query := `
+		DELETE FROM franchises
+		WHERE
+			slug = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, slug)
+	if err != nil {
+		return fmt.Errorf("failed to delete franchise: %v", err)
+	}
+
+	return repository.CheckAffected(result, "franchise", slug)
+}
+
+func (r *franchisePostgresRepo) List(ctx context.Context, q *models.FranchiseQuery) ([]*models.Franchise, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM franchises`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count franchises: %v", err)
+	}
+
+	query := `
+		SELECT` + franchiseColumns + `
+		FROM
+			franchises
+		ORDER BY
+			name,
+			id
+		LIMIT $1
+		OFFSET $2
+	`
+
+	franchises, err := r.listFranchises(ctx, query, q.Limit, q.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return franchises, total, nil
+}
+
+func (r *franchisePostgresRepo) ListByMovie(ctx context.Context, movieID int) ([]*models.Franchise, error) {
+	query := `
+		SELECT` + franchiseColumns + `
+		FROM
+			franchises
+		WHERE
+			id IN (
+				SELECT
+					franchise_id
+				FROM
+					franchise_movies
+				WHERE
+					movie_id = $1
+			)
+		ORDER BY
+			name,
+			id
+	`
+
+	return r.listFranchises(ctx, query, movieID)
+}
+
+func (r *franchisePostgresRepo) listFranchises(ctx context.Context, query string, args ...any) ([]*models.Franchise, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list franchises: %v", err)
+	}
+	defer rows.Close()
+
+	franchises := []*models.Franchise{}
+	for rows.Next() {
+		f, err := scanFranchise(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan franchise: %v", err)
+		}
+		franchises = append(franchises, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list franchises: %v", err)
+	}
+
+	if err := loadMovies(ctx, r.db, franchises...); err != nil {
+		return nil, err
+	}
+
+	return franchises, nil
+}
+
+func (r *franchisePostgresRepo) SetMovies(ctx context.Context, franchiseID int, movieIDs []int) ([]*models.FranchiseMovie, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			id
+		FROM
+			franchises
+		WHERE
+			id = $1
+		FOR UPDATE
+	`, franchiseID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("franchise", franchiseID)
+		}
+		return nil, fmt.Errorf("failed to lock franchise: %v", err)
+	}
+
+	var missing sql.NullInt64
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			MIN(o.movie_id)
+		FROM
+			UNNEST($1::INT[]) AS o (movie_id)
+			LEFT JOIN movies m ON m.id = o.movie_id
+			AND m.deleted_at IS NULL
+		WHERE
+			m.id IS NULL
+	`, pq.Array(movieIDs)).Scan(&missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check franchise movies: %v", err)
+	}
+
+	if missing.Valid {
+		return nil, repository.NotFound("movie", missing.Int64)
+	}
+
+	query := `
+		DELETE FROM franchise_movies
+		WHERE
+			franchise_id = $1
+			AND NOT movie_id = ANY ($2::INT[])
+			AND movie_id IN (
+				SELECT
+					id
+				FROM
+					movies
+				WHERE
+					deleted_at IS NULL
+			)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, franchiseID, pq.Array(movieIDs)); err != nil {
+		return nil, fmt.Errorf("failed to remove franchise movies: %v", err)
+	}
+
+	query = `
+		INSERT INTO
+			franchise_movies (
+				franchise_id,
+				movie_id,
+				position
+			)
+		SELECT
+			$1,
+			o.movie_id,
+			o.ordinality - 1
+		FROM
+			UNNEST($2::INT[]) WITH ORDINALITY AS o (movie_id, ordinality)
+		ON CONFLICT (franchise_id, movie_id) DO UPDATE
+		SET
+			position = EXCLUDED.position
+	`
+
+	if _, err := tx.ExecContext(ctx, query, franchiseID, pq.Array(movieIDs)); err != nil {
+		return nil, fmt.Errorf("failed to set franchise movies: %w", repository.Error(err))
+	}
+
+	f := &models.Franchise{ID: franchiseID}
+	if err := loadMovies(ctx, tx, f); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit franchise movies: %v", err)
+	}
+
+	return f.Movies, nil
+}
+
+// loadMovies fills visible members of franchises with a single query
+func loadMovies(ctx context.Context, db repository.DBTX, franchises ...*models.Franchise) error {
+	if len(franchises) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.Franchise, len(franchises))
+	ids := make([]int, 0, len(franchises))
+	for _, f := range franchises {
+		f.Movies = []*models.FranchiseMovie{}
+		byID[f.ID] = f
+		ids = append(ids, f.ID)
+	}
+
+	// positions are renumbered so that hidden members leave no holes
+	query := `
+		SELECT
+			fm.franchise_id,
+			fm.movie_id,
+			m.title,
+			m.release_date,
+			ROW_NUMBER() OVER (
+				PARTITION BY
+					fm.franchise_id
+				ORDER BY
+					fm.position,
+					fm.movie_id
+			) - 1 AS idx
+		FROM
+			franchise_movies fm
+			JOIN movies m ON m.id = fm.movie_id
+		WHERE
+			fm.franchise_id = ANY ($1::INT[])
+			AND m.deleted_at IS NULL
+		ORDER BY
+			fm.franchise_id,
+			idx
+	`
+
+	rows, err := db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to list franchise movies: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var franchiseID int
+		var m models.FranchiseMovie
+
+		if err := rows.Scan(&franchiseID, &m.MovieID, &m.Title, &m.ReleaseDate, &m.Position); err != nil {
+			return fmt.Errorf("failed to scan franchise movie: %v", err)
+		}
+
+		f := byID[franchiseID]
+		f.Movies = append(f.Movies, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list franchise movies: %v", err)
+	}
+
+	return nil
+}
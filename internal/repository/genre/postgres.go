@@ -0,0 +1,198 @@
+// Package genre provides communication application with db for genres vocabulary
+package genre
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+	"github.com/lib/pq"
+)
+
+// Repository interface describes functions which object must implements to store genres
+type Repository interface {
+	Create(context.Context, *models.Genre) error
+	GetBySlug(context.Context, string) (*models.Genre, error)
+	Update(ctx context.Context, slug string, genre *models.Genre) (*models.Genre, error)
+	Delete(context.Context, string) error
+	List(context.Context) ([]*models.Genre, error)
+	// ExistingSlugs returns those of given slugs which are present in vocabulary
+	ExistingSlugs(context.Context, []string) ([]string, error)
+}
+
+type genrePostgresRepo struct {
+	db *sql.DB
+}
+
+// NewGenrePostgresRepo creates new instance of genrePostgresRepo
+func NewGenrePostgresRepo(db *sql.DB) Repository {
+	return &genrePostgresRepo{db}
+}
+
+func (r *genrePostgresRepo) Create(ctx context.Context, genre *models.Genre) error {
+	query := `
+		INSERT INTO
+			genres (
+				slug,
+				name
+			)
+		VALUES
+			($1, $2)
+		RETURNING
+			id
+	`
+
+	if err := r.db.QueryRowContext(ctx, query, genre.Slug, genre.Name).Scan(&genre.ID); err != nil {
+		return fmt.Errorf("failed to create genre: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *genrePostgresRepo) GetBySlug(ctx context.Context, slug string) (*models.Genre, error) {
+	query := `
+		SELECT
+			id,
+			slug,
+			name
+		FROM
+			genres
+		WHERE
+			slug = $1
+	`
+
+	var genre models.Genre
+
+	err := r.db.QueryRowContext(ctx, query, slug).Scan(&genre.ID, &genre.Slug, &genre.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("genre", slug)
+		}
+		return nil, fmt.Errorf("failed to get genre: %v", err)
+	}
+
+	return &genre, nil
+}
+
+func (r *genrePostgresRepo) Update(ctx context.Context, slug string, genre *models.Genre) (*models.Genre, error) {
+	query := `
+		UPDATE
+			genres
+		SET slug = $1,
+			name = $2
+		WHERE
+			slug = $3
+		RETURNING
+			id,
+			slug,
+			name
+	`
+
+	var updated models.Genre
+
+	err := r.db.QueryRowContext(ctx, query, genre.Slug, genre.Name, slug).Scan(
+		&updated.ID,
+		&updated.Slug,
+		&updated.Name,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("genre", slug)
+		}
+		return nil, fmt.Errorf("failed to update genre: %w", repository.Error(err))
+	}
+
+	return &updated, nil
+}
+
+func (r *genrePostgresRepo) Delete(ctx context.Context, slug string) error {
+	query := `
+		DELETE FROM genres
+		WHERE
+			slug = $1
+	`
+
+	// genres used by movies are protected by foreign key and end up as conflict
+	result, err := r.db.ExecContext(ctx, query, slug)
+	if err != nil {
+		return fmt.Errorf("failed to delete genre: %w", repository.Error(err))
+	}
+
+	return repository.CheckAffected(result, "genre", slug)
+}
+
+func (r *genrePostgresRepo) List(ctx context.Context) ([]*models.Genre, error) {
+	query := `
+		SELECT
+			id,
+			slug,
+			name
+		FROM
+			genres
+		ORDER BY
+			name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list genres: %v", err)
+	}
+
+	defer rows.Close()
+
+	genres := []*models.Genre{}
+
+	for rows.Next() {
+		var genre models.Genre
+
+		if err := rows.Scan(&genre.ID, &genre.Slug, &genre.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan genre: %v", err)
+		}
+
+		genres = append(genres, &genre)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return genres, nil
+}
+
+func (r *genrePostgresRepo) ExistingSlugs(ctx context.Context, slugs []string) ([]string, error) {
+	query := `
+		SELECT
+			slug
+		FROM
+			genres
+		WHERE
+			slug = ANY($1)
+	`
+
+	var existing []string
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(slugs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check genres: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var slug string
+
+		if err := rows.Scan(&slug); err != nil {
+			return nil, fmt.Errorf("failed to scan genre slug: %v", err)
+		}
+
+		existing = append(existing, slug)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return existing, nil
+}
@@ -0,0 +1,117 @@
+package movie
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+	"github.com/lib/pq"
+)
+
+// directorSeparator splits co-directors written as "A, B" or "A & B", the same way people migration does
+var directorSeparator = regexp.MustCompile(`\s*[,&]\s*`)
+
+// splitDirectors returns names of directors without empty ones and ones repeated in other case
+func splitDirectors(director string) []string {
+	names := []string{}
+	for _, name := range directorSeparator.Split(strings.TrimSpace(director), -1) {
+		repeated := slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) })
+		if name != "" && !repeated {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// syncDirectorCredits makes director credits of the movie follow its director field. Names are matched
+// to people case-insensitively, missing people are created, and when several people share the name
+// the one with the lowest id is credited. It must be called within the transaction which changes the movie
+func syncDirectorCredits(ctx context.Context, db repository.DBTX, movieID int, director string) error {
+	names := pq.Array(splitDirectors(director))
+
+	query := `
+		DELETE FROM movie_credits mc USING people p
+		WHERE
+			p.id = mc.person_id
+			AND mc.movie_id = $1
+			AND mc.role = 'director'
+			AND LOWER(p.name) NOT IN (
+				SELECT
+					LOWER(n)
+				FROM
+					UNNEST($2::TEXT[]) AS n
+			)
+	`
+
+	if _, err := db.ExecContext(ctx, query, movieID, names); err != nil {
+		return fmt.Errorf("failed to clear director credits: %v", err)
+	}
+
+	query = `
+		INSERT INTO
+			people (name)
+		SELECT
+			n
+		FROM
+			UNNEST($1::TEXT[]) AS n
+		WHERE
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					people p
+				WHERE
+					LOWER(p.name) = LOWER(n)
+			)
+	`
+
+	if _, err := db.ExecContext(ctx, query, names); err != nil {
+		return fmt.Errorf("failed to create directors: %w", repository.Error(err))
+	}
+
+	query = `
+		INSERT INTO
+			movie_credits (
+				movie_id,
+				person_id,
+				role
+			)
+		SELECT
+			$1,
+			MIN(p.id),
+			'director'
+		FROM
+			people p
+		WHERE
+			LOWER(p.name) IN (
+				SELECT
+					LOWER(n)
+				FROM
+					UNNEST($2::TEXT[]) AS n
+			)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					movie_credits mc
+					JOIN people cp ON cp.id = mc.person_id
+				WHERE
+					mc.movie_id = $1
+					AND mc.role = 'director'
+					AND LOWER(cp.name) = LOWER(p.name)
+			)
+		GROUP BY
+			LOWER(p.name)
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := db.ExecContext(ctx, query, movieID, names); err != nil {
+		return fmt.Errorf("failed to add director credits: %w", repository.Error(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package movie
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitDirectors(t *testing.T) {
+	tests := map[string][]string{
+		"":                                  {},
+		"Christopher Nolan":                 {"Christopher Nolan"},
+		"Lana Wachowski & Lilly Wachowski":  {"Lana Wachowski", "Lilly Wachowski"},
+		" Joel Coen, Ethan Coen ,joel coen": {"Joel Coen", "Ethan Coen"},
+		"Nolan, ":                           {"Nolan"},
+	}
+
+	for input, expected := range tests {
+		if got := splitDirectors(input); !slices.Equal(got, expected) {
+			t.Errorf("splitDirectors(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
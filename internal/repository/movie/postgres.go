@@ -4,20 +4,203 @@ package movie
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+	"github.com/lib/pq"
 )
 
 // Repository interface describes functions which object must implements to communicate with db
+// Director credits follow director field, they are replaced when movie is created or its director is edited
 type Repository interface {
 	Create(context.Context, *models.Movie) error
 	GetByID(context.Context, int) (*models.Movie, error)
-	Update(context.Context, *models.Movie) (*models.Movie, error)
-	Delete(context.Context, int) error
-	List(context.Context) ([]*models.Movie, error)
+	// Update locks the movie, lets update change it and stores the result within one transaction.
+	// Tags are written only when update changes them, missing tags are created
+	Update(ctx context.Context, id int, update func(*models.Movie) error) (*models.Movie, error)
+	// Delete moves the movie to trash and returns its state before deletion,
+	// non-zero version must match the stored one
+	Delete(ctx context.Context, id, version int) (*models.Movie, error)
+	ListDeleted(ctx context.Context, limit, offset int) ([]*models.Movie, int, error)
+	Restore(context.Context, int) (*models.Movie, error)
+	// Purge removes the movie from trash permanently together with its history and returns its last state
+	Purge(context.Context, int) (*models.Movie, error)
+	// ListRevisions returns history of the movie, oldest revision goes first
+	ListRevisions(ctx context.Context, movieID int) ([]*models.Revision, error)
+	GetRevision(ctx context.Context, movieID, revisionID int) (*models.Revision, error)
+	// GetRevisionAt returns the latest revision made not later than asOf
+	GetRevisionAt(ctx context.Context, movieID int, asOf time.Time) (*models.Revision, error)
+	List(context.Context, *models.MovieQuery) ([]*models.Movie, int, error)
+	Search(context.Context, *models.SearchQuery) ([]*models.SearchResult, int, error)
+	Suggest(context.Context, string, int) ([]*models.Suggestion, error)
+}
+
+// ratingAverage is average score of the movie, unrated movies go as zero
+const ratingAverage = `COALESCE((
+		SELECT
+			rs.average
+		FROM
+			movie_rating_stats rs
+		WHERE
+			rs.movie_id = movies.id
+	), 0)`
+
+// rating stats are selected with subqueries so movieColumns also work in RETURNING clauses
+const movieColumns = `
+	id,
+	title,
+	director,
+	release_date,
+	genre,
+	ARRAY(
+		SELECT
+			g.slug
+		FROM
+			movie_genres mg
+			JOIN genres g ON g.id = mg.genre_id
+		WHERE
+			mg.movie_id = movies.id
+		ORDER BY
+			g.slug
+	),
+	description,
+	original_title,
+	runtime,
+	countries,
+	languages,
+	certifications,
+	budget,
+	budget_currency,
+	box_office,
+	box_office_currency,
+	created_at,
+	updated_at,
+	version,
+	deleted_at,
+	` + ratingAverage + `,
+	COALESCE((
+		SELECT
+			rs.rating_count
+		FROM
+			movie_rating_stats rs
+		WHERE
+			rs.movie_id = movies.id
+	), 0),
+	COALESCE((
+		SELECT
+			rs.histogram
+		FROM
+			movie_rating_stats rs
+		WHERE
+			rs.movie_id = movies.id
+	), ARRAY_FILL(0, ARRAY[10])),
+	ARRAY(
+		SELECT
+			t.name
+		FROM
+			movie_tags mt
+			JOIN tags t ON t.id = mt.tag_id
+		WHERE
+			mt.movie_id = movies.id
+		ORDER BY
+			t.name
+	)
+`
+
+type sortColumn struct {
+	name string
+	// sqlType is used for casting cursor value in keyset condition
+	sqlType string
+	// join adds table which column comes from, tiebreaker is movie id column ordered together with it
+	join       string
+	tiebreaker string
+}
+
+// sortColumns maps sort fields of models.MovieQuery to table columns
+var sortColumns = map[string]sortColumn{
+	models.SortByTitle:       {"title", "TEXT", "", "id"},
+	models.SortByReleaseDate: {"release_date", "TIMESTAMPTZ", "", "id"},
+	models.SortByCreatedAt:   {"created_at", "TIMESTAMPTZ", "", "id"},
+	models.SortByUpdatedAt:   {"updated_at", "TIMESTAMPTZ", "", "id"},
+	// every movie has stats row, so (average, movie_id) index serves both order and keyset condition
+	models.SortByRating: {"rs.average", "NUMERIC", "JOIN movie_rating_stats rs ON rs.movie_id = movies.id", "rs.movie_id"},
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanMovie reads movieColumns from row, extra destinations are for columns selected after them
+func scanMovie(row rowScanner, extra ...any) (*models.Movie, error) {
+	var (
+		movie          models.Movie
+		runtime        sql.NullInt64
+		certifications []byte
+		budget         nullMoney
+		boxOffice      nullMoney
+		deletedAt      sql.NullTime
+		histogram      []int64
+	)
+
+	dest := []any{
+		&movie.ID,
+		&movie.Title,
+		&movie.Director,
+		&movie.ReleaseDate,
+		&movie.Genre,
+		pq.Array(&movie.Genres),
+		&movie.Description,
+		&movie.OriginalTitle,
+		&runtime,
+		pq.Array(&movie.Countries),
+		pq.Array(&movie.Languages),
+		&certifications,
+		&budget.amount,
+		&budget.currency,
+		&boxOffice.amount,
+		&boxOffice.currency,
+		&movie.CreatedAt,
+		&movie.UpdatedAt,
+		&movie.Version,
+		&deletedAt,
+		&movie.Rating.Average,
+		&movie.Rating.Count,
+		pq.Array(&histogram),
+		pq.Array(&movie.Tags),
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.Valid {
+		minutes := int(runtime.Int64)
+		movie.Runtime = &minutes
+	}
+
+	if err := json.Unmarshal(certifications, &movie.Certifications); err != nil {
+		return nil, fmt.Errorf("failed to decode certifications: %v", err)
+	}
+
+	movie.Budget = budget.money()
+	movie.BoxOffice = boxOffice.money()
+
+	if deletedAt.Valid {
+		movie.DeletedAt = &deletedAt.Time
+	}
+
+	movie.Rating.Histogram = make([]int, len(histogram))
+	for i, count := range histogram {
+		movie.Rating.Histogram[i] = int(count)
+	}
+
+	return &movie, nil
 }
 
 type moviePostgresRepo struct {
@@ -38,75 +221,106 @@ func (r *moviePostgresRepo) Create(ctx context.Context, movie *models.Movie) err
 				release_date,
 				genre,
 				description,
+				original_title,
+				runtime,
+				countries,
+				languages,
+				certifications,
+				budget,
+				budget_currency,
+				box_office,
+				box_office_currency,
 				created_at,
 				updated_at
 			)
 		VALUES
-			($1, $2, $3, $4, $5, $6, $7)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING
-			id
+			id,
+			created_at,
+			updated_at,
+			version
 	`
 
-	err := r.db.QueryRowContext(
-		ctx,
-		qurery,
-		movie.Title,
-		movie.Director,
-		movie.ReleaseDate,
-		movie.Genre,
-		movie.Description,
-		time.Now(),
-		time.Now(),
-	).Scan(&movie.ID)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	metadata, err := metadataArgs(movie)
+	if err != nil {
+		return err
+	}
+
+	args := append([]any{movie.Title, movie.Director, movie.ReleaseDate, movie.Genre, movie.Description}, metadata...)
+	args = append(args, time.Now(), time.Now())
 
+	err = tx.QueryRowContext(ctx, qurery, args...).Scan(&movie.ID, &movie.CreatedAt, &movie.UpdatedAt, &movie.Version)
+
+	if err != nil {
+		return fmt.Errorf("failed to create movie: %w", repository.Error(err))
+	}
+
+	if err := setGenres(ctx, tx, movie.ID, movie.Genres); err != nil {
+		return err
+	}
+
+	if err := syncDirectorCredits(ctx, tx, movie.ID, movie.Director); err != nil {
+		return err
+	}
+
+	created, err := getByID(ctx, tx, movie.ID, false)
 	if err != nil {
-		return fmt.Errorf("failed to create task: %v", err)
+		return err
+	}
+
+	if err := addRevision(ctx, tx, created, models.RevisionCreate); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit movie: %v", err)
+	}
+
+	*movie = *created
+
 	return nil
 }
 
 func (r *moviePostgresRepo) GetByID(ctx context.Context, id int) (*models.Movie, error) {
+	return getByID(ctx, r.db, id, false)
+}
+
+// getByID reads movie which is not in trash within db or transaction,
+// forUpdate locks the row until transaction ends
+func getByID(ctx context.Context, db repository.DBTX, id int, forUpdate bool) (*models.Movie, error) {
 	query := `
 		SELECT
-			id,
-			title,
-			director,
-			release_date,
-			genre,
-			description,
-			created_at,
-			updated_at
+			` + movieColumns + `
 		FROM
 			movies
 		WHERE
 			id = $1
+			AND deleted_at IS NULL
 	`
 
-	var movie models.Movie
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&movie.ID,
-		&movie.Title,
-		&movie.Director,
-		&movie.ReleaseDate,
-		&movie.Genre,
-		&movie.Description,
-		&movie.CreatedAt,
-		&movie.UpdatedAt,
-	)
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
 
+	movie, err := scanMovie(db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("movie not found")
+			return nil, repository.NotFound("movie", id)
 		}
 		return nil, fmt.Errorf("failed to get movie: %v", err)
 	}
 
-	return &movie, nil
+	return movie, nil
 }
 
-func (r *moviePostgresRepo) Update(ctx context.Context, movie *models.Movie) (*models.Movie, error) {
+func (r *moviePostgresRepo) Update(ctx context.Context, id int, update func(*models.Movie) error) (*models.Movie, error) {
 	query := `
 		UPDATE
 			movies
@@ -115,136 +329,458 @@ func (r *moviePostgresRepo) Update(ctx context.Context, movie *models.Movie) (*m
 			release_date = $3,
 			genre = $4,
 			description = $5,
-			updated_at = $6
+			original_title = $6,
+			runtime = $7,
+			countries = $8,
+			languages = $9,
+			certifications = $10,
+			budget = $11,
+			budget_currency = $12,
+			box_office = $13,
+			box_office_currency = $14,
+			updated_at = $15,
+			version = version + 1
 		WHERE
-			id = $7
-		RETURNING
-			id,
-			title,
-			director,
-			release_date,
-			genre,
-			description,
-			created_at,
-			updated_at
+			id = $16
 	`
 
-	oldMovie, err := r.GetByID(ctx, movie.ID)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("invalid movie id: %v", err)
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
 	}
+	defer tx.Rollback()
 
-	if movie.Title == "" {
-		movie.Title = oldMovie.Title
+	// row stays locked until commit, so concurrent updates can't interleave with update func
+	movie, err := getByID(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
 	}
 
-	if movie.Director == "" {
-		movie.Director = oldMovie.Director
+	director, tags := movie.Director, slices.Clone(movie.Tags)
+
+	if err := update(movie); err != nil {
+		return nil, err
 	}
 
-	if movie.ReleaseDate.IsZero() {
-		movie.ReleaseDate = oldMovie.ReleaseDate
+	metadata, err := metadataArgs(movie)
+	if err != nil {
+		return nil, err
 	}
 
-	if movie.Genre == "" {
-		movie.Genre = oldMovie.Genre
+	args := append([]any{movie.Title, movie.Director, movie.ReleaseDate, movie.Genre, movie.Description}, metadata...)
+	args = append(args, time.Now(), id)
+
+	_, err = tx.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update movie: %w", repository.Error(err))
 	}
 
-	if movie.Description == "" {
-		movie.Description = oldMovie.Description
+	if err := setGenres(ctx, tx, id, movie.Genres); err != nil {
+		return nil, err
 	}
 
-	var updatedMovie models.Movie
-	err = r.db.QueryRowContext(
-		ctx,
-		query,
-		movie.Title,
-		movie.Director,
-		movie.ReleaseDate,
-		movie.Genre,
-		movie.Description,
-		time.Now(),
-		movie.ID,
-	).Scan(
-		&updatedMovie.ID,
-		&updatedMovie.Title,
-		&updatedMovie.Director,
-		&updatedMovie.ReleaseDate,
-		&updatedMovie.Genre,
-		&updatedMovie.Description,
-		&updatedMovie.CreatedAt,
-		&updatedMovie.UpdatedAt,
-	)
+	// credits linked by hand are kept until director is edited
+	if movie.Director != director {
+		if err := syncDirectorCredits(ctx, tx, id, movie.Director); err != nil {
+			return nil, err
+		}
+	}
+
+	if !slices.Equal(movie.Tags, tags) {
+		if err := setTags(ctx, tx, id, movie.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedMovie, err := getByID(ctx, tx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := addRevision(ctx, tx, updatedMovie, models.RevisionUpdate); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit movie: %v", err)
+	}
+
+	return updatedMovie, nil
+}
+
+// nullMoney reads nullable amount and currency columns
+type nullMoney struct {
+	amount   sql.NullInt64
+	currency sql.NullString
+}
 
+func (m nullMoney) money() *models.Money {
+	if !m.amount.Valid {
+		return nil
+	}
+
+	return &models.Money{Amount: m.amount.Int64, Currency: m.currency.String}
+}
+
+// metadataArgs returns arguments for columns from original_title to box_office_currency,
+// unset values are stored as NULL or empty arrays
+func metadataArgs(movie *models.Movie) ([]any, error) {
+	certifications, err := json.Marshal(movie.Certifications)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update movie: %v", err)
+		return nil, fmt.Errorf("failed to encode certifications: %v", err)
+	}
+
+	if movie.Certifications == nil {
+		certifications = []byte("{}")
+	}
+
+	countries, languages := movie.Countries, movie.Languages
+	if countries == nil {
+		countries = []string{}
+	}
+	if languages == nil {
+		languages = []string{}
+	}
+
+	// lib/pq sends []byte as bytea, so JSON goes as text
+	args := []any{movie.OriginalTitle, movie.Runtime, pq.Array(countries), pq.Array(languages), string(certifications)}
+
+	for _, m := range []*models.Money{movie.Budget, movie.BoxOffice} {
+		if m == nil {
+			args = append(args, nil, nil)
+		} else {
+			args = append(args, m.Amount, m.Currency)
+		}
 	}
 
-	return &updatedMovie, nil
+	return args, nil
 }
 
-func (r *moviePostgresRepo) Delete(ctx context.Context, id int) error {
+// setGenres replaces genres of the movie, slugs must be present in genres vocabulary
+func setGenres(ctx context.Context, db repository.DBTX, movieID int, slugs []string) error {
 	query := `
-		DELETE FROM movies
+		DELETE FROM movie_genres
 		WHERE
-			id = $1
+			movie_id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query, id)
+	if _, err := db.ExecContext(ctx, query, movieID); err != nil {
+		return fmt.Errorf("failed to clear movie genres: %v", err)
+	}
 
-	if err != nil {
-		return fmt.Errorf("failed to delete movie: %v", err)
+	if len(slugs) == 0 {
+		return nil
+	}
+
+	query = `
+		INSERT INTO
+			movie_genres (
+				movie_id,
+				genre_id
+			)
+		SELECT
+			$1,
+			id
+		FROM
+			genres
+		WHERE
+			slug = ANY($2)
+	`
+
+	if _, err := db.ExecContext(ctx, query, movieID, pq.Array(slugs)); err != nil {
+		return fmt.Errorf("failed to set movie genres: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+// setTags replaces tags of the movie creating missing ones, names must be normalized
+func setTags(ctx context.Context, db repository.DBTX, movieID int, names []string) error {
+	if names == nil {
+		names = []string{}
+	}
+
+	query := `
+		DELETE FROM movie_tags mt USING tags t
+		WHERE
+			t.id = mt.tag_id
+			AND mt.movie_id = $1
+			AND t.name <> ALL ($2::TEXT[])
+	`
+
+	if _, err := db.ExecContext(ctx, query, movieID, pq.Array(names)); err != nil {
+		return fmt.Errorf("failed to clear movie tags: %v", err)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	query = `
+		INSERT INTO
+			tags (name)
+		SELECT
+			UNNEST($1::TEXT[])
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	if _, err := db.ExecContext(ctx, query, pq.Array(names)); err != nil {
+		return fmt.Errorf("failed to create tags: %w", repository.Error(err))
+	}
+
+	query = `
+		INSERT INTO
+			movie_tags (
+				movie_id,
+				tag_id
+			)
+		SELECT
+			$1,
+			id
+		FROM
+			tags
+		WHERE
+			name = ANY ($2::TEXT[])
+		ON CONFLICT (movie_id, tag_id) DO NOTHING
+	`
+
+	if _, err := db.ExecContext(ctx, query, movieID, pq.Array(names)); err != nil {
+		return fmt.Errorf("failed to set movie tags: %w", repository.Error(err))
 	}
 
 	return nil
 }
 
-func (r *moviePostgresRepo) List(ctx context.Context) ([]*models.Movie, error) {
+func (r *moviePostgresRepo) Delete(ctx context.Context, id, version int) (*models.Movie, error) {
+	query := `
+		UPDATE
+			movies
+		SET deleted_at = NOW(),
+			version = version + 1
+		WHERE
+			id = $1
+		RETURNING
+			` + movieColumns
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	movie, err := getByID(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 && movie.Version != version {
+		return nil, fmt.Errorf("movie %d is not at version %d: %w", id, version, models.ErrPreconditionFailed)
+	}
+
+	deleted, err := scanMovie(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete movie: %w", repository.Error(err))
+	}
+
+	if err := addRevision(ctx, tx, deleted, models.RevisionDelete); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit movie deletion: %v", err)
+	}
+
+	return movie, nil
+}
+
+func (r *moviePostgresRepo) ListDeleted(ctx context.Context, limit, offset int) ([]*models.Movie, int, error) {
+	countQuery := `
+		SELECT
+			COUNT(*)
+		FROM
+			movies
+		WHERE
+			deleted_at IS NOT NULL
+	`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted movies: %v", err)
+	}
+
 	query := `
 		SELECT
-			ID,
-			TITLE,
-			DIRECTOR,
-			RELEASE_DATE,
-			GENRE,
-			DESCRIPTION,
-			CREATED_AT,
-			UPDATED_AT
+			` + movieColumns + `
 		FROM
-			MOVIES
+			movies
+		WHERE
+			deleted_at IS NOT NULL
 		ORDER BY
-			UPDATED_AT DESC
+			deleted_at DESC,
+			id DESC
+		LIMIT $1
+		OFFSET $2
+	`
+
+	movies, err := r.listMovies(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movies, total, nil
+}
+
+func (r *moviePostgresRepo) Restore(ctx context.Context, id int) (*models.Movie, error) {
+	query := `
+		UPDATE
+			movies
+		SET deleted_at = NULL,
+			version = version + 1
+		WHERE
+			id = $1
+			AND deleted_at IS NOT NULL
+		RETURNING
+			` + movieColumns
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	movie, err := scanMovie(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("deleted movie", id)
+		}
+		return nil, fmt.Errorf("failed to restore movie: %w", repository.Error(err))
+	}
+
+	if err := addRevision(ctx, tx, movie, models.RevisionRestore); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit movie restore: %v", err)
+	}
+
+	return movie, nil
+}
+
+func (r *moviePostgresRepo) Purge(ctx context.Context, id int) (*models.Movie, error) {
+	query := `
+		SELECT
+			` + movieColumns + `
+		FROM
+			movies
+		WHERE
+			id = $1
+			AND deleted_at IS NOT NULL
+		FOR UPDATE
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	movie, err := scanMovie(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("deleted movie", id)
+		}
+		return nil, fmt.Errorf("failed to get deleted movie: %v", err)
+	}
+
+	query = `
+		DELETE FROM movies
+		WHERE
+			id = $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return nil, fmt.Errorf("failed to purge movie: %w", repository.Error(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit movie purge: %v", err)
+	}
+
+	return movie, nil
+}
+
+func (r *moviePostgresRepo) List(ctx context.Context, q *models.MovieQuery) ([]*models.Movie, int, error) {
+	conditions, args := buildMovieFilter(q)
+
+	countQuery := `
+		SELECT
+			COUNT(*)
+		FROM
+			movies
+	` + whereClause(conditions)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count movies: %v", err)
+	}
+
+	column := sortColumns[q.SortBy]
+	order, seek := "ASC", ">"
+	if q.SortDesc {
+		order, seek = "DESC", "<"
+	}
+
+	// keyset condition skips everything up to the cursor row,
+	// so pages don't shift when rows are inserted before it
+	if q.After != nil {
+		args = append(args, q.After.Value, q.After.ID)
+		conditions = append(conditions, fmt.Sprintf(
+			"(%s, %s) %s ($%d::%s, $%d)",
+			column.name, column.tiebreaker, seek, len(args)-1, column.sqlType, len(args),
+		))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s
+		FROM
+			movies
+			%s
+		%s
+		ORDER BY
+			%s %s,
+			%s %s
+		LIMIT $%d
+		OFFSET $%d
+	`, movieColumns, column.join, whereClause(conditions), column.name, order, column.tiebreaker, order, len(args)+1, len(args)+2)
+
+	movies, err := r.listMovies(ctx, query, append(args, q.Limit, q.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movies, total, nil
+}
 
+func (r *moviePostgresRepo) listMovies(ctx context.Context, query string, args ...any) ([]*models.Movie, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list movies: %v", err)
 	}
 
 	defer rows.Close()
 
-	var movies []*models.Movie
+	movies := []*models.Movie{}
 
 	for rows.Next() {
-		var movie models.Movie
-
-		err := rows.Scan(
-			&movie.ID,
-			&movie.Title,
-			&movie.Director,
-			&movie.ReleaseDate,
-			&movie.Genre,
-			&movie.Description,
-			&movie.CreatedAt,
-			&movie.UpdatedAt,
-		)
-
+		movie, err := scanMovie(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan movie: %v", err)
 		}
 
-		movies = append(movies, &movie)
+		movies = append(movies, movie)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -253,3 +789,131 @@ func (r *moviePostgresRepo) List(ctx context.Context) ([]*models.Movie, error) {
 
 	return movies, nil
 }
+
+// buildMovieFilter returns conditions and their arguments for the query filters,
+// movies in trash are always filtered out
+func buildMovieFilter(q *models.MovieQuery) ([]string, []any) {
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []any
+	)
+
+	if q.Genre != "" {
+		args = append(args, q.Genre)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT
+				1
+			FROM
+				movie_genres mg
+				JOIN genres g ON g.id = mg.genre_id
+			WHERE
+				mg.movie_id = movies.id
+				AND g.slug = LOWER($%d)
+		)`, len(args)))
+	}
+
+	if len(q.Tags) > 0 {
+		args = append(args, pq.Array(q.Tags))
+		tagged := fmt.Sprintf(`
+			FROM
+				movie_tags mt
+				JOIN tags t ON t.id = mt.tag_id
+			WHERE
+				mt.movie_id = movies.id
+				AND t.name = ANY ($%d::TEXT[])
+		`, len(args))
+
+		// names in q.Tags are unique, so movie having all of them matches each one exactly once
+		if q.AnyTag {
+			conditions = append(conditions, "EXISTS (SELECT 1 "+tagged+")")
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(SELECT COUNT(*) %s) = CARDINALITY($%d::TEXT[])", tagged, len(args)))
+		}
+	}
+
+	if q.Director != "" {
+		args = append(args, "%"+escapeLike(q.Director)+"%")
+		conditions = append(conditions, fmt.Sprintf("director ILIKE $%d", len(args)))
+	}
+
+	if q.ReleasedFrom != nil {
+		args = append(args, *q.ReleasedFrom)
+		conditions = append(conditions, fmt.Sprintf("release_date >= $%d", len(args)))
+	}
+
+	if q.ReleasedTo != nil {
+		args = append(args, *q.ReleasedTo)
+		conditions = append(conditions, fmt.Sprintf("release_date <= $%d", len(args)))
+	}
+
+	if q.OriginalTitle != "" {
+		args = append(args, "%"+escapeLike(q.OriginalTitle)+"%")
+		conditions = append(conditions, fmt.Sprintf("original_title ILIKE $%d", len(args)))
+	}
+
+	// containment operators are served by GIN indexes on arrays and certifications
+	if q.Country != "" {
+		args = append(args, pq.Array([]string{q.Country}))
+		conditions = append(conditions, fmt.Sprintf("countries @> $%d::TEXT[]", len(args)))
+	}
+
+	if q.Language != "" {
+		args = append(args, pq.Array([]string{q.Language}))
+		conditions = append(conditions, fmt.Sprintf("languages @> $%d::TEXT[]", len(args)))
+	}
+
+	if q.CertificationCountry != "" {
+		args = append(args, q.CertificationCountry, q.Certification)
+		conditions = append(conditions, fmt.Sprintf("certifications @> JSONB_BUILD_OBJECT($%d::TEXT, $%d::TEXT)", len(args)-1, len(args)))
+	}
+
+	bounds := []struct {
+		column string
+		value  int
+		op     string
+	}{
+		{"runtime", q.RuntimeMin, ">="},
+		{"runtime", q.RuntimeMax, "<="},
+		{"budget", q.BudgetMin, ">="},
+		{"budget", q.BudgetMax, "<="},
+		{"box_office", q.BoxOfficeMin, ">="},
+		{"box_office", q.BoxOfficeMax, "<="},
+	}
+
+	// money amounts are comparable only within the same currency
+	currencyColumns := map[string]bool{}
+
+	for _, b := range bounds {
+		if b.value == 0 {
+			continue
+		}
+
+		args = append(args, b.value)
+		conditions = append(conditions, fmt.Sprintf("%s %s $%d", b.column, b.op, len(args)))
+
+		if b.column != "runtime" {
+			currencyColumns[b.column+"_currency"] = true
+		}
+	}
+
+	for _, column := range []string{"budget_currency", "box_office_currency"} {
+		if currencyColumns[column] {
+			args = append(args, q.Currency)
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+		}
+	}
+
+	return conditions, args
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
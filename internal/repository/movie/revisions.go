@@ -0,0 +1,168 @@
+package movie
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+const revisionColumns = `
+	id,
+	movie_id,
+	version,
+	action,
+	COALESCE(changed_by, ''),
+	created_at,
+	snapshot
+`
+
+// addRevision stores state of the movie with subject of the principal in ctx as its author,
+// it must be called within the transaction which changes the movie
+func addRevision(ctx context.Context, db repository.DBTX, movie *models.Movie, action string) error {
+	query := `
+		INSERT INTO
+			movie_revisions (
+				movie_id,
+				version,
+				action,
+				changed_by,
+				snapshot
+			)
+		VALUES
+			($1, $2, $3, $4, $5)
+	`
+
+	var changedBy sql.NullString
+	if principal, ok := auth.FromContext(ctx); ok {
+		changedBy = sql.NullString{String: principal.Subject, Valid: true}
+	}
+
+	snapshot, err := json.Marshal(movie)
+	if err != nil {
+		return fmt.Errorf("failed to encode movie snapshot: %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, query, movie.ID, movie.Version, action, changedBy, snapshot); err != nil {
+		return fmt.Errorf("failed to add movie revision: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func scanRevision(row rowScanner) (*models.Revision, error) {
+	var (
+		revision models.Revision
+		snapshot []byte
+	)
+
+	err := row.Scan(
+		&revision.ID,
+		&revision.MovieID,
+		&revision.Version,
+		&revision.Action,
+		&revision.ChangedBy,
+		&revision.CreatedAt,
+		&snapshot,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode movie snapshot: %v", err)
+	}
+
+	return &revision, nil
+}
+
+func (r *moviePostgresRepo) ListRevisions(ctx context.Context, movieID int) ([]*models.Revision, error) {
+	query := `
+		SELECT
+			` + revisionColumns + `
+		FROM
+			movie_revisions
+		WHERE
+			movie_id = $1
+		ORDER BY
+			created_at,
+			id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list movie revisions: %v", err)
+	}
+
+	defer rows.Close()
+
+	revisions := []*models.Revision{}
+
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan movie revision: %v", err)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return revisions, nil
+}
+
+func (r *moviePostgresRepo) GetRevision(ctx context.Context, movieID, revisionID int) (*models.Revision, error) {
+	query := `
+		SELECT
+			` + revisionColumns + `
+		FROM
+			movie_revisions
+		WHERE
+			movie_id = $1
+			AND id = $2
+	`
+
+	revision, err := scanRevision(r.db.QueryRowContext(ctx, query, movieID, revisionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("revision", revisionID)
+		}
+		return nil, fmt.Errorf("failed to get movie revision: %v", err)
+	}
+
+	return revision, nil
+}
+
+func (r *moviePostgresRepo) GetRevisionAt(ctx context.Context, movieID int, asOf time.Time) (*models.Revision, error) {
+	query := `
+		SELECT
+			` + revisionColumns + `
+		FROM
+			movie_revisions
+		WHERE
+			movie_id = $1
+			AND created_at <= $2
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT 1
+	`
+
+	revision, err := scanRevision(r.db.QueryRowContext(ctx, query, movieID, asOf))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("movie %d as of %s: %w", movieID, asOf.Format(time.RFC3339), models.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get movie revision: %v", err)
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,206 @@
+package movie
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+
+func (r *moviePostgresRepo) Search(ctx context.Context, q *models.SearchQuery) ([]*models.SearchResult, int, error) {
+	tsQuery := buildTSQuery(q.Query)
+	if tsQuery == "" {
+		return []*models.SearchResult{}, 0, nil
+	}
+
+	countQuery := `
+		SELECT
+			COUNT(*)
+		FROM
+			movies
+		WHERE
+			search_vector @@ TO_TSQUERY('english', $1)
+			AND deleted_at IS NULL
+	`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, tsQuery).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %v", err)
+	}
+
+	query := `
+		SELECT
+			` + movieColumns + `,
+			TS_RANK(search_vector, tsq) AS rank,
+			TS_HEADLINE('english', COALESCE(description, ''), tsq, $2) AS snippet
+		FROM
+			movies,
+			TO_TSQUERY('english', $1) tsq
+		WHERE
+			search_vector @@ tsq
+			AND deleted_at IS NULL
+		ORDER BY
+			rank DESC,
+			id
+		LIMIT $3
+		OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tsQuery, headlineOptions, q.Limit, q.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search movies: %v", err)
+	}
+
+	defer rows.Close()
+
+	results := make([]*models.SearchResult, 0, q.Limit)
+
+	for rows.Next() {
+		var result models.SearchResult
+
+		movie, err := scanMovie(rows, &result.Rank, &result.Snippet)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan search result: %v", err)
+		}
+
+		result.Movie = movie
+		results = append(results, &result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %v", err)
+	}
+
+	return results, total, nil
+}
+
+// buildTSQuery converts user search input into to_tsquery syntax.
+// Words are combined with AND, "quoted text" becomes a phrase,
+// word* is a prefix match, -word excludes and OR makes alternatives
+func buildTSQuery(input string) string {
+	var (
+		terms    []string
+		operator string
+	)
+
+	add := func(term string) {
+		if term == "" {
+			return
+		}
+		if len(terms) > 0 {
+			if operator == "" {
+				operator = "&"
+			}
+			terms = append(terms, operator)
+		}
+		terms = append(terms, term)
+		operator = ""
+	}
+
+	for input != "" {
+		input = strings.TrimLeftFunc(input, unicode.IsSpace)
+
+		switch {
+		case input == "":
+		case input[0] == '"':
+			phrase, rest, _ := strings.Cut(input[1:], `"`)
+			add(joinLexemes(phrase, "<->"))
+			input = rest
+		default:
+			end := strings.IndexFunc(input, unicode.IsSpace)
+			if end < 0 {
+				end = len(input)
+			}
+			word := input[:end]
+			input = input[end:]
+
+			if word == "OR" {
+				if len(terms) > 0 {
+					operator = "|"
+				}
+				continue
+			}
+
+			negate := strings.HasPrefix(word, "-")
+			prefix := strings.HasSuffix(word, "*")
+			term := joinLexemes(strings.Trim(word, "-*"), "<->")
+			if term == "" {
+				continue
+			}
+			if prefix && !strings.HasPrefix(term, "(") {
+				term += ":*"
+			}
+			if negate {
+				term = "!" + term
+			}
+			add(term)
+		}
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// joinLexemes splits text into letter and digit runs joined with operator,
+// so that no tsquery syntax from user input reaches the database
+func joinLexemes(text, operator string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(words) > 1 {
+		return "(" + strings.Join(words, " "+operator+" ") + ")"
+	}
+
+	return strings.Join(words, "")
+}
+
+func (r *moviePostgresRepo) Suggest(ctx context.Context, text string, limit int) ([]*models.Suggestion, error) {
+	// <% operator is word similarity, it matches typed text against any part of the title
+	// and is served by trigram indexes on title and director
+	query := `
+		SELECT
+			id,
+			title,
+			director,
+			release_date,
+			GREATEST(WORD_SIMILARITY($1, title), WORD_SIMILARITY($1, director)) AS score
+		FROM
+			movies
+		WHERE
+			($1 <% title OR $1 <% director)
+			AND deleted_at IS NULL
+		ORDER BY
+			score DESC,
+			title
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, text, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest movies: %v", err)
+	}
+
+	defer rows.Close()
+
+	suggestions := make([]*models.Suggestion, 0, limit)
+
+	for rows.Next() {
+		var s models.Suggestion
+
+		if err := rows.Scan(&s.ID, &s.Title, &s.Director, &s.ReleaseDate, &s.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion: %v", err)
+		}
+
+		suggestions = append(suggestions, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return suggestions, nil
+}
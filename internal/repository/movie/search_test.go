@@ -0,0 +1,25 @@
+package movie
+
+import "testing"
+
+func TestBuildTSQuery(t *testing.T) {
+	tests := map[string]string{
+		"":                        "",
+		"godfather":               "godfather",
+		"dark knight":             "dark & knight",
+		`"dark knight" nolan`:     "(dark <-> knight) & nolan",
+		"incep*":                  "incep:*",
+		"heat OR ronin":           "heat | ronin",
+		"batman -robin":           "batman & !robin",
+		"spider-man":              "(spider <-> man)",
+		"it's & (drop) | table:*": "(it <-> s) & drop & table:*",
+		`"unterminated phrase`:    "(unterminated <-> phrase)",
+		"OR leading":              "leading",
+	}
+
+	for input, expected := range tests {
+		if got := buildTSQuery(input); got != expected {
+			t.Errorf("buildTSQuery(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
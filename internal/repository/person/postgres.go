@@ -0,0 +1,361 @@
+// Package person provides communication application with db for people and their movie credits
+package person
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+// Repository interface describes functions which object must implements to store people and credits
+type Repository interface {
+	Create(context.Context, *models.Person) error
+	GetByID(context.Context, int) (*models.Person, error)
+	Update(context.Context, *models.Person) (*models.Person, error)
+	Delete(context.Context, int) error
+	List(context.Context, *models.PersonQuery) ([]*models.Person, int, error)
+	AddCredit(context.Context, *models.Credit) error
+	DeleteCredit(ctx context.Context, movieID, creditID int) error
+	ListMovieCredits(ctx context.Context, movieID int) ([]*models.Credit, error)
+	ListPersonCredits(ctx context.Context, personID int) ([]*models.Credit, error)
+}
+
+type personPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewPersonPostgresRepo creates new instance of personPostgresRepo
+func NewPersonPostgresRepo(db *sql.DB) Repository {
+	return &personPostgresRepo{db}
+}
+
+const personColumns = `
+	id,
+	name,
+	birth_date,
+	COALESCE(bio, ''),
+	created_at,
+	updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPerson(row rowScanner) (*models.Person, error) {
+	var (
+		person    models.Person
+		birthDate sql.NullTime
+	)
+
+	err := row.Scan(
+		&person.ID,
+		&person.Name,
+		&birthDate,
+		&person.Bio,
+		&person.CreatedAt,
+		&person.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if birthDate.Valid {
+		person.BirthDate = &birthDate.Time
+	}
+
+	return &person, nil
+}
+
+func (r *personPostgresRepo) Create(ctx context.Context, person *models.Person) error {
+	query := `
+		INSERT INTO
+			people (
+				name,
+				birth_date,
+				bio
+			)
+		VALUES
+			($1, $2, $3)
+		RETURNING
+			id,
+			created_at,
+			updated_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		person.Name,
+		person.BirthDate,
+		person.Bio,
+	).Scan(&person.ID, &person.CreatedAt, &person.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create person: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *personPostgresRepo) GetByID(ctx context.Context, id int) (*models.Person, error) {
+	query := `
+		SELECT
+			` + personColumns + `
+		FROM
+			people
+		WHERE
+			id = $1
+	`
+
+	person, err := scanPerson(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("person", id)
+		}
+		return nil, fmt.Errorf("failed to get person: %v", err)
+	}
+
+	return person, nil
+}
+
+func (r *personPostgresRepo) Update(ctx context.Context, person *models.Person) (*models.Person, error) {
+	query := `
+		UPDATE
+			people
+		SET name = $1,
+			birth_date = $2,
+			bio = $3
+		WHERE
+			id = $4
+		RETURNING
+			` + personColumns
+
+	updated, err := scanPerson(r.db.QueryRowContext(
+		ctx,
+		query,
+		person.Name,
+		person.BirthDate,
+		person.Bio,
+		person.ID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("person", person.ID)
+		}
+		return nil, fmt.Errorf("failed to update person: %w", repository.Error(err))
+	}
+
+	return updated, nil
+}
+
+func (r *personPostgresRepo) Delete(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM people
+		WHERE
+			id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete person: %w", repository.Error(err))
+	}
+
+	return repository.CheckAffected(result, "person", id)
+}
+
+func (r *personPostgresRepo) List(ctx context.Context, q *models.PersonQuery) ([]*models.Person, int, error) {
+	where := ""
+	args := []any{}
+	if q.Name != "" {
+		where = "WHERE STRPOS(LOWER(name), LOWER($1)) > 0"
+		args = append(args, q.Name)
+	}
+
+	countQuery := `
+		SELECT
+			COUNT(*)
+		FROM
+			people
+	` + where
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count people: %v", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s
+		FROM
+			people
+		%s
+		ORDER BY
+			name,
+			id
+		LIMIT $%d
+		OFFSET $%d
+	`, personColumns, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, q.Limit, q.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list people: %v", err)
+	}
+
+	defer rows.Close()
+
+	people := make([]*models.Person, 0, q.Limit)
+
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan person: %v", err)
+		}
+
+		people = append(people, person)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %v", err)
+	}
+
+	return people, total, nil
+}
+
+func (r *personPostgresRepo) AddCredit(ctx context.Context, credit *models.Credit) error {
+	query := `
+		INSERT INTO
+			movie_credits (
+				movie_id,
+				person_id,
+				role,
+				character_name
+			)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING
+			id
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		credit.MovieID,
+		credit.PersonID,
+		credit.Role,
+		credit.CharacterName,
+	).Scan(&credit.ID)
+
+	if repository.IsForeignKeyViolation(err) {
+		return fmt.Errorf("movie %d or person %d: %w", credit.MovieID, credit.PersonID, models.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add credit: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *personPostgresRepo) DeleteCredit(ctx context.Context, movieID, creditID int) error {
+	query := `
+		DELETE FROM movie_credits
+		WHERE
+			id = $1
+			AND movie_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, creditID, movieID)
+	if err != nil {
+		return fmt.Errorf("failed to delete credit: %v", err)
+	}
+
+	return repository.CheckAffected(result, "credit", creditID)
+}
+
+func (r *personPostgresRepo) ListMovieCredits(ctx context.Context, movieID int) ([]*models.Credit, error) {
+	query := `
+		SELECT
+			c.id,
+			c.movie_id,
+			m.title,
+			c.person_id,
+			p.name,
+			c.role,
+			c.character_name
+		FROM
+			movie_credits c
+			JOIN people p ON p.id = c.person_id
+			JOIN movies m ON m.id = c.movie_id
+		WHERE
+			c.movie_id = $1
+		ORDER BY
+			c.role,
+			c.id
+	`
+
+	return r.listCredits(ctx, query, movieID)
+}
+
+func (r *personPostgresRepo) ListPersonCredits(ctx context.Context, personID int) ([]*models.Credit, error) {
+	query := `
+		SELECT
+			c.id,
+			c.movie_id,
+			m.title,
+			c.person_id,
+			p.name,
+			c.role,
+			c.character_name
+		FROM
+			movie_credits c
+			JOIN people p ON p.id = c.person_id
+			JOIN movies m ON m.id = c.movie_id
+		WHERE
+			c.person_id = $1
+			AND m.deleted_at IS NULL
+		ORDER BY
+			m.release_date DESC,
+			c.id
+	`
+
+	return r.listCredits(ctx, query, personID)
+}
+
+func (r *personPostgresRepo) listCredits(ctx context.Context, query string, args ...any) ([]*models.Credit, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credits: %v", err)
+	}
+
+	defer rows.Close()
+
+	credits := []*models.Credit{}
+
+	for rows.Next() {
+		var credit models.Credit
+
+		err := rows.Scan(
+			&credit.ID,
+			&credit.MovieID,
+			&credit.MovieTitle,
+			&credit.PersonID,
+			&credit.PersonName,
+			&credit.Role,
+			&credit.CharacterName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan credit: %v", err)
+		}
+
+		credits = append(credits, &credit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %v", err)
+	}
+
+	return credits, nil
+}
@@ -0,0 +1,156 @@
+// Package rating provides communication application with db for movie ratings
+package rating
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+	"github.com/lib/pq"
+)
+
+// Repository interface describes functions which object must implements to store ratings,
+// aggregates of movie are maintained by db trigger on every change
+type Repository interface {
+	// Set creates rating of the movie which isn't in trash or changes score of existing one
+	Set(context.Context, *models.Rating) error
+	Get(ctx context.Context, userID, movieID int) (*models.Rating, error)
+	Delete(ctx context.Context, userID, movieID int) error
+	Stats(ctx context.Context, movieID int) (*models.RatingStats, error)
+}
+
+type ratingPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewRatingPostgresRepo creates new instance of ratingPostgresRepo
+func NewRatingPostgresRepo(db *sql.DB) Repository {
+	return &ratingPostgresRepo{db}
+}
+
+func (r *ratingPostgresRepo) Set(ctx context.Context, rating *models.Rating) error {
+	query := `
+		INSERT INTO
+			movie_ratings (
+				user_id,
+				movie_id,
+				score
+			)
+		SELECT
+			$1::INT,
+			$2::INT,
+			$3::SMALLINT
+		WHERE
+			EXISTS (
+				SELECT
+					1
+				FROM
+					movies
+				WHERE
+					id = $2
+					AND deleted_at IS NULL
+			)
+		ON CONFLICT (user_id, movie_id) DO UPDATE
+		SET
+			score = EXCLUDED.score
+		RETURNING
+			created_at,
+			updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, rating.UserID, rating.MovieID, rating.Score).Scan(
+		&rating.CreatedAt,
+		&rating.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.NotFound("movie", rating.MovieID)
+		}
+		return fmt.Errorf("failed to set rating: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *ratingPostgresRepo) Get(ctx context.Context, userID, movieID int) (*models.Rating, error) {
+	query := `
+		SELECT
+			user_id,
+			movie_id,
+			score,
+			created_at,
+			updated_at
+		FROM
+			movie_ratings
+		WHERE
+			user_id = $1
+			AND movie_id = $2
+	`
+
+	var rating models.Rating
+
+	err := r.db.QueryRowContext(ctx, query, userID, movieID).Scan(
+		&rating.UserID,
+		&rating.MovieID,
+		&rating.Score,
+		&rating.CreatedAt,
+		&rating.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("rating of movie", movieID)
+		}
+		return nil, fmt.Errorf("failed to get rating: %v", err)
+	}
+
+	return &rating, nil
+}
+
+func (r *ratingPostgresRepo) Delete(ctx context.Context, userID, movieID int) error {
+	query := `
+		DELETE FROM movie_ratings
+		WHERE
+			user_id = $1
+			AND movie_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, movieID)
+	if err != nil {
+		return fmt.Errorf("failed to delete rating: %v", err)
+	}
+
+	return repository.CheckAffected(result, "rating of movie", movieID)
+}
+
+// Stats returns aggregates of movie ratings, movie which nobody rated has zero stats
+func (r *ratingPostgresRepo) Stats(ctx context.Context, movieID int) (*models.RatingStats, error) {
+	query := `
+		SELECT
+			average,
+			rating_count,
+			histogram
+		FROM
+			movie_rating_stats
+		WHERE
+			movie_id = $1
+	`
+
+	var (
+		stats     models.RatingStats
+		histogram []int64
+	)
+
+	err := r.db.QueryRowContext(ctx, query, movieID).Scan(&stats.Average, &stats.Count, pq.Array(&histogram))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get rating stats: %v", err)
+	}
+
+	stats.Histogram = make([]int, models.MaxRatingScore)
+	for i, count := range histogram {
+		stats.Histogram[i] = int(count)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,253 @@
+// Package relation provides communication application with db for relations between movies
+package relation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+// Repository interface describes functions which object must implements to store relations between movies.
+// Sequels and prequels order movies by story, so they must not form cycles, remakes and spin-offs may
+type Repository interface {
+	// Create links two different movies which aren't in trash, it fails with models.ErrConflict
+	// when movies are already related or sequel or prequel would close a cycle
+	Create(context.Context, *models.Relation) error
+	Delete(ctx context.Context, movieID, relatedID int) error
+	// ListRelated returns movies related to movie in both directions, movies in trash are hidden
+	ListRelated(ctx context.Context, movieID int) ([]*models.RelatedMovie, error)
+}
+
+type relationPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewRelationPostgresRepo creates new instance of relationPostgresRepo
+func NewRelationPostgresRepo(db *sql.DB) Repository {
+	return &relationPostgresRepo{db}
+}
+
+func (r *relationPostgresRepo) Create(ctx context.Context, rel *models.Relation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// the lock conflicts with itself and with row exclusive lock of every insert, update and delete,
+	// so two relations can't close a cycle together, while relations stay readable. Deletes of
+	// relations and cascades of movie purge wait for the commit too
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE movie_relations IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock relations: %v", err)
+	}
+
+	for _, id := range []int{rel.MovieID, rel.RelatedID} {
+		if err := checkMovie(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := checkCycle(ctx, tx, rel); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO
+			movie_relations (
+				movie_id,
+				related_id,
+				type
+			)
+		VALUES
+			($1, $2, $3)
+		RETURNING
+			created_at
+	`
+
+	if err := tx.QueryRowContext(ctx, query, rel.MovieID, rel.RelatedID, rel.Type).Scan(&rel.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create relation: %w", repository.Error(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit relation: %v", err)
+	}
+
+	return nil
+}
+
+func (r *relationPostgresRepo) Delete(ctx context.Context, movieID, relatedID int) error {
+	query := `
+		DELETE FROM movie_relations
+		WHERE
+			movie_id = $1
+			AND related_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, movieID, relatedID)
+	if err != nil {
+		return fmt.Errorf("failed to delete relation: %v", err)
+	}
+
+	return repository.CheckAffected(result, "relation", fmt.Sprintf("%d-%d", movieID, relatedID))
+}
+
+func (r *relationPostgresRepo) ListRelated(ctx context.Context, movieID int) ([]*models.RelatedMovie, error) {
+	if err := checkMovie(ctx, r.db, movieID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			m.id,
+			m.title,
+			m.release_date,
+			mr.type,
+			FALSE
+		FROM
+			movie_relations mr
+			JOIN movies m ON m.id = mr.related_id
+		WHERE
+			mr.movie_id = $1
+			AND m.deleted_at IS NULL
+		UNION ALL
+		SELECT
+			m.id,
+			m.title,
+			m.release_date,
+			mr.type,
+			TRUE
+		FROM
+			movie_relations mr
+			JOIN movies m ON m.id = mr.movie_id
+		WHERE
+			mr.related_id = $1
+			AND m.deleted_at IS NULL
+		ORDER BY
+			3,
+			1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list related movies: %v", err)
+	}
+	defer rows.Close()
+
+	related := []*models.RelatedMovie{}
+	for rows.Next() {
+		var m models.RelatedMovie
+		if err := rows.Scan(&m.MovieID, &m.Title, &m.ReleaseDate, &m.Type, &m.Inverse); err != nil {
+			return nil, fmt.Errorf("failed to scan related movie: %v", err)
+		}
+		related = append(related, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list related movies: %v", err)
+	}
+
+	return related, nil
+}
+
+// storyOrder returns which movie of sequel or prequel relation comes first in the story,
+// ok is false for relations of other types
+func storyOrder(rel *models.Relation) (earlier, later int, ok bool) {
+	switch rel.Type {
+	case models.RelationSequel:
+		return rel.RelatedID, rel.MovieID, true
+	case models.RelationPrequel:
+		return rel.MovieID, rel.RelatedID, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// checkCycle returns models.ErrConflict when sequel or prequel closes a cycle of story order,
+// which runs from movie to its sequel, relations of other types are skipped
+func checkCycle(ctx context.Context, db repository.DBTX, rel *models.Relation) error {
+	earlier, later, ok := storyOrder(rel)
+	if !ok {
+		return nil
+	}
+
+	// new relation closes a cycle when earlier movie already follows later one
+	query := `
+		WITH RECURSIVE story (earlier, later) AS (
+			SELECT
+				related_id,
+				movie_id
+			FROM
+				movie_relations
+			WHERE
+				type = 'sequel'
+			UNION ALL
+			SELECT
+				movie_id,
+				related_id
+			FROM
+				movie_relations
+			WHERE
+				type = 'prequel'
+		),
+		following (movie_id) AS (
+			SELECT
+				$1::INT
+			UNION
+			SELECT
+				s.later
+			FROM
+				story s
+				JOIN following f ON f.movie_id = s.earlier
+		)
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					following
+				WHERE
+					movie_id = $2
+			)
+	`
+
+	var cycle bool
+	if err := db.QueryRowContext(ctx, query, later, earlier).Scan(&cycle); err != nil {
+		return fmt.Errorf("failed to check relation cycle: %v", err)
+	}
+
+	if cycle {
+		return fmt.Errorf("%w: movie %d already follows movie %d, relation would close a cycle", models.ErrConflict, earlier, later)
+	}
+
+	return nil
+}
+
+// checkMovie returns not found error when movie doesn't exist or is in trash
+func checkMovie(ctx context.Context, db repository.DBTX, movieID int) error {
+	var exists bool
+
+	err := db.QueryRowContext(ctx, `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					movies
+				WHERE
+					id = $1
+					AND deleted_at IS NULL
+			)
+	`, movieID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check movie: %v", err)
+	}
+
+	if !exists {
+		return repository.NotFound("movie", movieID)
+	}
+
+	return nil
+}
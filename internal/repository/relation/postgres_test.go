@@ -0,0 +1,28 @@
+package relation
+
+import (
+	"testing"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+func TestStoryOrder(t *testing.T) {
+	tests := []struct {
+		rel            models.Relation
+		earlier, later int
+		ok             bool
+	}{
+		// movie 2 is sequel of movie 1
+		{models.Relation{MovieID: 2, RelatedID: 1, Type: models.RelationSequel}, 1, 2, true},
+		// movie 1 is prequel of movie 2
+		{models.Relation{MovieID: 1, RelatedID: 2, Type: models.RelationPrequel}, 1, 2, true},
+		{models.Relation{MovieID: 2, RelatedID: 1, Type: models.RelationRemake}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		earlier, later, ok := storyOrder(&tt.rel)
+		if earlier != tt.earlier || later != tt.later || ok != tt.ok {
+			t.Errorf("storyOrder(%+v) = %d, %d, %t, expected %d, %d, %t", tt.rel, earlier, later, ok, tt.earlier, tt.later, tt.ok)
+		}
+	}
+}
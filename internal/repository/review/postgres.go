@@ -0,0 +1,401 @@
+// Package review provides communication application with db for movie reviews and their votes
+package review
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+// Repository interface describes functions which object must implements to store reviews,
+// vote counters of reviews are maintained by db trigger on every vote
+type Repository interface {
+	// Create adds review of the movie which isn't in trash
+	Create(context.Context, *models.Review) error
+	Get(ctx context.Context, id int) (*models.Review, error)
+	// Update changes text of the review and sends it back to moderation
+	Update(context.Context, *models.Review) (*models.Review, error)
+	Delete(ctx context.Context, id int) error
+	// List returns reviews matching query, reviews of movie which doesn't exist or is in trash
+	// are not found
+	List(context.Context, *models.ReviewQuery) ([]*models.Review, int, error)
+	// Moderate changes status of the review, it fails with models.ErrConflict
+	// when review isn't in status from anymore
+	Moderate(ctx context.Context, id int, from string, moderation *models.Moderation, moderator string) (*models.Review, error)
+	// Vote creates vote of the user for approved review or changes existing one
+	Vote(ctx context.Context, reviewID, userID int, vote *models.ReviewVote) error
+	DeleteVote(ctx context.Context, reviewID, userID int) error
+}
+
+type reviewPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewReviewPostgresRepo creates new instance of reviewPostgresRepo
+func NewReviewPostgresRepo(db *sql.DB) Repository {
+	return &reviewPostgresRepo{db}
+}
+
+const reviewColumns = `
+	id,
+	movie_id,
+	user_id,
+	title,
+	body,
+	status,
+	moderated_by,
+	moderation_note,
+	moderated_at,
+	helpful_count,
+	unhelpful_count,
+	created_at,
+	updated_at
+`
+
+// sortColumns maps sort fields of models.ReviewQuery to table columns
+var sortColumns = map[string]string{
+	models.ReviewSortByCreatedAt: "created_at",
+	models.ReviewSortByHelpful:   "helpful_count",
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReview(row rowScanner) (*models.Review, error) {
+	var (
+		review      models.Review
+		moderatedBy sql.NullString
+		moderatedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&review.ID,
+		&review.MovieID,
+		&review.UserID,
+		&review.Title,
+		&review.Body,
+		&review.Status,
+		&moderatedBy,
+		&review.ModerationNote,
+		&moderatedAt,
+		&review.HelpfulCount,
+		&review.UnhelpfulCount,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	review.ModeratedBy = moderatedBy.String
+	if moderatedAt.Valid {
+		review.ModeratedAt = &moderatedAt.Time
+	}
+
+	return &review, nil
+}
+
+func (r *reviewPostgresRepo) Create(ctx context.Context, review *models.Review) error {
+	query := `
+		INSERT INTO
+			reviews (
+				movie_id,
+				user_id,
+				title,
+				body,
+				status
+			)
+		SELECT
+			$1::INT,
+			$2::INT,
+			$3::TEXT,
+			$4::TEXT,
+			$5::TEXT
+		WHERE
+			EXISTS (
+				SELECT
+					1
+				FROM
+					movies
+				WHERE
+					id = $1
+					AND deleted_at IS NULL
+			)
+		RETURNING
+			id,
+			created_at,
+			updated_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		review.MovieID,
+		review.UserID,
+		review.Title,
+		review.Body,
+		review.Status,
+	).Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.NotFound("movie", review.MovieID)
+		}
+		return fmt.Errorf("failed to create review: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *reviewPostgresRepo) Get(ctx context.Context, id int) (*models.Review, error) {
+	query := `
+		SELECT` + reviewColumns + `
+		FROM
+			reviews
+		WHERE
+			id = $1
+	`
+
+	review, err := scanReview(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("review", id)
+		}
+		return nil, fmt.Errorf("failed to get review: %v", err)
+	}
+
+	return review, nil
+}
+
+func (r *reviewPostgresRepo) Update(ctx context.Context, review *models.Review) (*models.Review, error) {
+	query := `
+		UPDATE
+			reviews
+		SET
+			title = $2,
+			body = $3,
+			status = $4,
+			moderated_by = NULL,
+			moderation_note = '',
+			moderated_at = NULL
+		WHERE
+			id = $1
+		RETURNING` + reviewColumns
+
+	updated, err := scanReview(r.db.QueryRowContext(ctx, query, review.ID, review.Title, review.Body, models.ReviewPending))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("review", review.ID)
+		}
+		return nil, fmt.Errorf("failed to update review: %w", repository.Error(err))
+	}
+
+	return updated, nil
+}
+
+func (r *reviewPostgresRepo) Delete(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM reviews
+		WHERE
+			id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete review: %v", err)
+	}
+
+	return repository.CheckAffected(result, "review", id)
+}
+
+func (r *reviewPostgresRepo) List(ctx context.Context, q *models.ReviewQuery) ([]*models.Review, int, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	addCondition := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if q.MovieID != 0 {
+		if err := r.checkMovie(ctx, q.MovieID); err != nil {
+			return nil, 0, err
+		}
+
+		addCondition("movie_id = $%d", q.MovieID)
+	}
+
+	if q.Status != "" {
+		addCondition("status = $%d", q.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countQuery := `
+		SELECT
+			COUNT(*)
+		FROM
+			reviews
+	` + where
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count reviews: %v", err)
+	}
+
+	column, ok := sortColumns[q.SortBy]
+	if !ok {
+		column = sortColumns[models.ReviewSortByCreatedAt]
+	}
+
+	order := "ASC"
+	if q.SortDesc {
+		order = "DESC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s
+		FROM
+			reviews
+		%s
+		ORDER BY
+			%s %s,
+			id %s
+		LIMIT $%d
+		OFFSET $%d
+	`, reviewColumns, where, column, order, order, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, q.Limit, q.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list reviews: %v", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]*models.Review, 0, q.Limit)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan review: %v", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list reviews: %v", err)
+	}
+
+	return reviews, total, nil
+}
+
+func (r *reviewPostgresRepo) Moderate(ctx context.Context, id int, from string, moderation *models.Moderation, moderator string) (*models.Review, error) {
+	query := `
+		UPDATE
+			reviews
+		SET
+			status = $3,
+			moderation_note = $4,
+			moderated_by = $5,
+			moderated_at = NOW()
+		WHERE
+			id = $1
+			AND status = $2
+		RETURNING` + reviewColumns
+
+	review, err := scanReview(r.db.QueryRowContext(ctx, query, id, from, moderation.Status, moderation.Note, moderator))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: review %d is not %s anymore", models.ErrConflict, id, from)
+		}
+		return nil, fmt.Errorf("failed to moderate review: %w", repository.Error(err))
+	}
+
+	return review, nil
+}
+
+func (r *reviewPostgresRepo) Vote(ctx context.Context, reviewID, userID int, vote *models.ReviewVote) error {
+	query := `
+		INSERT INTO
+			review_votes (
+				review_id,
+				user_id,
+				helpful
+			)
+		SELECT
+			$1::INT,
+			$2::INT,
+			$3::BOOLEAN
+		WHERE
+			EXISTS (
+				SELECT
+					1
+				FROM
+					reviews
+				WHERE
+					id = $1
+					AND status = 'approved'
+			)
+		ON CONFLICT (review_id, user_id) DO UPDATE
+		SET
+			helpful = EXCLUDED.helpful
+	`
+
+	result, err := r.db.ExecContext(ctx, query, reviewID, userID, vote.Helpful)
+	if err != nil {
+		return fmt.Errorf("failed to vote for review: %w", repository.Error(err))
+	}
+
+	return repository.CheckAffected(result, "approved review", reviewID)
+}
+
+func (r *reviewPostgresRepo) DeleteVote(ctx context.Context, reviewID, userID int) error {
+	query := `
+		DELETE FROM review_votes
+		WHERE
+			review_id = $1
+			AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, reviewID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete review vote: %v", err)
+	}
+
+	return repository.CheckAffected(result, "vote for review", reviewID)
+}
+
+// checkMovie returns not found error when movie doesn't exist or is in trash
+func (r *reviewPostgresRepo) checkMovie(ctx context.Context, movieID int) error {
+	var exists bool
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					movies
+				WHERE
+					id = $1
+					AND deleted_at IS NULL
+			)
+	`, movieID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check movie: %v", err)
+	}
+
+	if !exists {
+		return repository.NotFound("movie", movieID)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+// Package role provides communication application with db for roles of API callers
+package role
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+// Repository interface describes functions which object must implements to store role assignments
+type Repository interface {
+	Get(ctx context.Context, subject string) (*models.RoleAssignment, error)
+	List(context.Context) ([]*models.RoleAssignment, error)
+	// Set creates assignment or changes role of existing one
+	Set(context.Context, *models.RoleAssignment) error
+	Delete(ctx context.Context, subject string) error
+}
+
+type rolePostgresRepo struct {
+	db *sql.DB
+}
+
+// NewRolePostgresRepo creates new instance of rolePostgresRepo
+func NewRolePostgresRepo(db *sql.DB) Repository {
+	return &rolePostgresRepo{db}
+}
+
+func (r *rolePostgresRepo) Get(ctx context.Context, subject string) (*models.RoleAssignment, error) {
+	query := `
+		SELECT
+			subject,
+			role,
+			created_at,
+			updated_at
+		FROM
+			user_roles
+		WHERE
+			subject = $1
+	`
+
+	var a models.RoleAssignment
+
+	err := r.db.QueryRowContext(ctx, query, subject).Scan(&a.Subject, &a.Role, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("role of", subject)
+		}
+		return nil, fmt.Errorf("failed to get role: %v", err)
+	}
+
+	return &a, nil
+}
+
+func (r *rolePostgresRepo) List(ctx context.Context) ([]*models.RoleAssignment, error) {
+	query := `
+		SELECT
+			subject,
+			role,
+			created_at,
+			updated_at
+		FROM
+			user_roles
+		ORDER BY
+			subject
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %v", err)
+	}
+	defer rows.Close()
+
+	assignments := []*models.RoleAssignment{}
+	for rows.Next() {
+		var a models.RoleAssignment
+		if err := rows.Scan(&a.Subject, &a.Role, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan role: %v", err)
+		}
+		assignments = append(assignments, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list roles: %v", err)
+	}
+
+	return assignments, nil
+}
+
+func (r *rolePostgresRepo) Set(ctx context.Context, a *models.RoleAssignment) error {
+	query := `
+		INSERT INTO
+			user_roles (
+				subject,
+				role
+			)
+		VALUES
+			($1, $2)
+		ON CONFLICT (subject) DO UPDATE
+		SET role = EXCLUDED.role
+		RETURNING
+			created_at,
+			updated_at
+	`
+
+	if err := r.db.QueryRowContext(ctx, query, a.Subject, a.Role).Scan(&a.CreatedAt, &a.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to set role: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *rolePostgresRepo) Delete(ctx context.Context, subject string) error {
+	query := `
+		DELETE FROM user_roles
+		WHERE
+			subject = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, subject)
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %v", err)
+	}
+
+	return repository.CheckAffected(result, "role of", subject)
+}
@@ -0,0 +1,165 @@
+// Package tag provides communication application with db for free-form tags of movies
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+// Repository interface describes functions which object must implements to read tags of movies.
+// Tags are attached and detached through movie repository update, so every change gets a new movie version
+// and revision. Tag names must be normalized by caller, usage counts skip movies in trash and unused tags
+type Repository interface {
+	ListByMovie(ctx context.Context, movieID int) ([]string, error)
+	// Suggest returns tags which name or one of its words starts with prefix, whole name matches go first
+	Suggest(ctx context.Context, prefix string, limit int) ([]*models.Tag, error)
+	// MostUsed returns tags ordered by number of movies which have them
+	MostUsed(ctx context.Context, limit int) ([]*models.Tag, error)
+}
+
+type tagPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewTagPostgresRepo creates new instance of tagPostgresRepo
+func NewTagPostgresRepo(db *sql.DB) Repository {
+	return &tagPostgresRepo{db}
+}
+
+// usageQuery counts movies of each used tag, the verb is replaced with condition on tag name
+const usageQuery = `
+	SELECT
+		t.name,
+		COUNT(*) AS usage
+	FROM
+		tags t
+		JOIN movie_tags mt ON mt.tag_id = t.id
+		JOIN movies m ON m.id = mt.movie_id
+	WHERE
+		m.deleted_at IS NULL
+		AND %s
+	GROUP BY
+		t.name
+`
+
+func (r *tagPostgresRepo) ListByMovie(ctx context.Context, movieID int) ([]string, error) {
+	if err := r.checkMovie(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			t.name
+		FROM
+			movie_tags mt
+			JOIN tags t ON t.id = mt.tag_id
+		WHERE
+			mt.movie_id = $1
+		ORDER BY
+			t.name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list movie tags: %v", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %v", err)
+		}
+		tags = append(tags, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list movie tags: %v", err)
+	}
+
+	return tags, nil
+}
+
+func (r *tagPostgresRepo) Suggest(ctx context.Context, prefix string, limit int) ([]*models.Tag, error) {
+	// both patterns are served by trigram index on tag name
+	query := fmt.Sprintf(usageQuery, `(t.name LIKE $1 || '%' OR t.name LIKE '% ' || $1 || '%')`) + `
+		ORDER BY
+			t.name LIKE $1 || '%' DESC,
+			usage DESC,
+			t.name
+		LIMIT $2
+	`
+
+	return r.listTags(ctx, query, escapeLike(prefix), limit)
+}
+
+func (r *tagPostgresRepo) MostUsed(ctx context.Context, limit int) ([]*models.Tag, error) {
+	query := fmt.Sprintf(usageQuery, "TRUE") + `
+		ORDER BY
+			usage DESC,
+			t.name
+		LIMIT $1
+	`
+
+	return r.listTags(ctx, query, limit)
+}
+
+func (r *tagPostgresRepo) listTags(ctx context.Context, query string, args ...any) ([]*models.Tag, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %v", err)
+	}
+	defer rows.Close()
+
+	tags := []*models.Tag{}
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %v", err)
+		}
+		tags = append(tags, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %v", err)
+	}
+
+	return tags, nil
+}
+
+// checkMovie returns not found error when movie doesn't exist or is in trash
+func (r *tagPostgresRepo) checkMovie(ctx context.Context, movieID int) error {
+	var exists bool
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					movies
+				WHERE
+					id = $1
+					AND deleted_at IS NULL
+			)
+	`, movieID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check movie: %v", err)
+	}
+
+	if !exists {
+		return repository.NotFound("movie", movieID)
+	}
+
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
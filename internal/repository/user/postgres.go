@@ -0,0 +1,149 @@
+// Package user provides communication application with db for user accounts and their refresh tokens
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+// Repository interface describes functions which object must implements to store users
+type Repository interface {
+	Create(context.Context, *models.User) error
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	// GetByEmail looks user up ignoring case of email
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	UpdatePassword(ctx context.Context, id int, hash string) error
+
+	CreateRefreshToken(context.Context, *models.RefreshToken) error
+	GetRefreshToken(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// RotateRefreshToken revokes active token with id and stores next one in the same transaction,
+	// models.ErrNotFound is returned when the token was revoked already
+	RotateRefreshToken(ctx context.Context, id int, next *models.RefreshToken) error
+	RevokeRefreshFamily(ctx context.Context, familyID string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID int) error
+}
+
+type userPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewUserPostgresRepo creates new instance of userPostgresRepo
+func NewUserPostgresRepo(db *sql.DB) Repository {
+	return &userPostgresRepo{db}
+}
+
+const userColumns = `
+	id,
+	email,
+	name,
+	password_hash,
+	created_at,
+	updated_at
+`
+
+func scanUser(row *sql.Row) (*models.User, error) {
+	var user models.User
+
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *userPostgresRepo) Create(ctx context.Context, user *models.User) error {
+	query := `
+		INSERT INTO
+			users (
+				email,
+				name,
+				password_hash
+			)
+		VALUES
+			($1, $2, $3)
+		RETURNING
+			id,
+			created_at,
+			updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, user.Email, user.Name, user.PasswordHash).Scan(
+		&user.ID,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *userPostgresRepo) GetByID(ctx context.Context, id int) (*models.User, error) {
+	query := `
+		SELECT` + userColumns + `
+		FROM
+			users
+		WHERE
+			id = $1
+	`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("user", id)
+		}
+		return nil, fmt.Errorf("failed to get user: %v", err)
+	}
+
+	return user, nil
+}
+
+func (r *userPostgresRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `
+		SELECT` + userColumns + `
+		FROM
+			users
+		WHERE
+			LOWER(email) = LOWER($1)
+	`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("user", email)
+		}
+		return nil, fmt.Errorf("failed to get user: %v", err)
+	}
+
+	return user, nil
+}
+
+func (r *userPostgresRepo) UpdatePassword(ctx context.Context, id int, hash string) error {
+	query := `
+		UPDATE
+			users
+		SET password_hash = $1
+		WHERE
+			id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, hash, id)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %v", err)
+	}
+
+	return repository.CheckAffected(result, "user", id)
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+)
+
+func (r *userPostgresRepo) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	return createRefreshToken(ctx, r.db, token)
+}
+
+func createRefreshToken(ctx context.Context, db repository.DBTX, token *models.RefreshToken) error {
+	query := `
+		INSERT INTO
+			refresh_tokens (
+				user_id,
+				family_id,
+				token_hash,
+				expires_at
+			)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := db.QueryRowContext(ctx, query, token.UserID, token.FamilyID, token.Hash, token.ExpiresAt).Scan(
+		&token.ID,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", repository.Error(err))
+	}
+
+	return nil
+}
+
+func (r *userPostgresRepo) GetRefreshToken(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			family_id,
+			token_hash,
+			expires_at,
+			revoked_at,
+			created_at
+		FROM
+			refresh_tokens
+		WHERE
+			token_hash = $1
+	`
+
+	var (
+		token     models.RefreshToken
+		revokedAt sql.NullTime
+	)
+
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.Hash,
+		&token.ExpiresAt,
+		&revokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.NotFound("refresh token", "with given hash")
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %v", err)
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return &token, nil
+}
+
+func (r *userPostgresRepo) RotateRefreshToken(ctx context.Context, id int, next *models.RefreshToken) error {
+	query := `
+		UPDATE
+			refresh_tokens
+		SET revoked_at = NOW()
+		WHERE
+			id = $1
+			AND revoked_at IS NULL
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %v", err)
+	}
+
+	if err := repository.CheckAffected(result, "active refresh token", id); err != nil {
+		return err
+	}
+
+	if err := createRefreshToken(ctx, tx, next); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit refresh token rotation: %v", err)
+	}
+
+	return nil
+}
+
+func (r *userPostgresRepo) RevokeRefreshFamily(ctx context.Context, familyID string) error {
+	query := `
+		UPDATE
+			refresh_tokens
+		SET revoked_at = NOW()
+		WHERE
+			family_id = $1
+			AND revoked_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %v", err)
+	}
+
+	return nil
+}
+
+func (r *userPostgresRepo) RevokeUserRefreshTokens(ctx context.Context, userID int) error {
+	query := `
+		UPDATE
+			refresh_tokens
+		SET revoked_at = NOW()
+		WHERE
+			user_id = $1
+			AND revoked_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,434 @@
+// Package watchlist provides communication application with db for watchlists and watch history of users
+package watchlist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository"
+	"github.com/lib/pq"
+)
+
+// Repository interface describes functions which object must implements to store watchlists and history.
+// Entries of movies in trash are hidden, they come back when movie is restored
+type Repository interface {
+	// AddToWatchlist appends movie which isn't in trash to the end of watchlist,
+	// adding movie which is already there keeps its position
+	AddToWatchlist(ctx context.Context, userID int, item *models.WatchlistItem) error
+	RemoveFromWatchlist(ctx context.Context, userID, movieID int) error
+	ListWatchlist(ctx context.Context, userID int) ([]*models.WatchlistItem, error)
+	// ReorderWatchlist locks the list, lets check compare movieIDs with movies in it
+	// and moves them to positions of their indexes within one transaction
+	ReorderWatchlist(ctx context.Context, userID int, movieIDs []int, check func(current []int) error) error
+	// AddToHistory appends movie which isn't in trash to the end of history,
+	// watched date and rewatch count of movie which is already there are replaced
+	AddToHistory(ctx context.Context, userID int, entry *models.HistoryEntry) error
+	RemoveFromHistory(ctx context.Context, userID, movieID int) error
+	ListHistory(ctx context.Context, userID int) ([]*models.HistoryEntry, error)
+	// ReorderHistory works like ReorderWatchlist
+	ReorderHistory(ctx context.Context, userID int, movieIDs []int, check func(current []int) error) error
+}
+
+type watchlistPostgresRepo struct {
+	db *sql.DB
+}
+
+// NewWatchlistPostgresRepo creates new instance of watchlistPostgresRepo
+func NewWatchlistPostgresRepo(db *sql.DB) Repository {
+	return &watchlistPostgresRepo{db}
+}
+
+func (r *watchlistPostgresRepo) AddToWatchlist(ctx context.Context, userID int, item *models.WatchlistItem) error {
+	query := `
+		WITH added AS (
+			INSERT INTO
+				watchlist_items (
+					user_id,
+					movie_id,
+					position
+				)
+			SELECT
+				$1::INT,
+				m.id,
+				COALESCE((
+					SELECT
+						MAX(position) + 1
+					FROM
+						watchlist_items
+					WHERE
+						user_id = $1
+				), 0)
+			FROM
+				movies m
+			WHERE
+				m.id = $2
+				AND m.deleted_at IS NULL
+			-- no-op update makes RETURNING yield existing row
+			ON CONFLICT (user_id, movie_id) DO UPDATE
+			SET
+				position = watchlist_items.position
+			RETURNING
+				movie_id,
+				position,
+				added_at
+		)
+		SELECT
+			a.position,
+			a.added_at,
+			m.title,
+			m.release_date
+		FROM
+			added a
+			JOIN movies m ON m.id = a.movie_id
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockUser(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, query, userID, item.MovieID).Scan(
+		&item.Position,
+		&item.AddedAt,
+		&item.Title,
+		&item.ReleaseDate,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.NotFound("movie", item.MovieID)
+		}
+		return fmt.Errorf("failed to add movie to watchlist: %w", repository.Error(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit watchlist item: %v", err)
+	}
+
+	return nil
+}
+
+func (r *watchlistPostgresRepo) RemoveFromWatchlist(ctx context.Context, userID, movieID int) error {
+	return r.remove(ctx, "watchlist_items", "watchlist", userID, movieID)
+}
+
+func (r *watchlistPostgresRepo) ListWatchlist(ctx context.Context, userID int) ([]*models.WatchlistItem, error) {
+	query := `
+		SELECT
+			w.movie_id,
+			m.title,
+			m.release_date,
+			w.position,
+			w.added_at
+		FROM
+			watchlist_items w
+			JOIN movies m ON m.id = w.movie_id
+		WHERE
+			w.user_id = $1
+			AND m.deleted_at IS NULL
+		ORDER BY
+			w.position,
+			w.movie_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlist: %v", err)
+	}
+	defer rows.Close()
+
+	items := []*models.WatchlistItem{}
+	for rows.Next() {
+		var item models.WatchlistItem
+		if err := rows.Scan(&item.MovieID, &item.Title, &item.ReleaseDate, &item.Position, &item.AddedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist item: %v", err)
+		}
+		items = append(items, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list watchlist: %v", err)
+	}
+
+	return items, nil
+}
+
+func (r *watchlistPostgresRepo) ReorderWatchlist(ctx context.Context, userID int, movieIDs []int, check func([]int) error) error {
+	return r.reorder(ctx, "watchlist_items", userID, movieIDs, check)
+}
+
+func (r *watchlistPostgresRepo) AddToHistory(ctx context.Context, userID int, entry *models.HistoryEntry) error {
+	query := `
+		WITH added AS (
+			INSERT INTO
+				watch_history (
+					user_id,
+					movie_id,
+					position,
+					watched_at,
+					rewatch_count
+				)
+			SELECT
+				$1::INT,
+				m.id,
+				COALESCE((
+					SELECT
+						MAX(position) + 1
+					FROM
+						watch_history
+					WHERE
+						user_id = $1
+				), 0),
+				$3::DATE,
+				$4::INT
+			FROM
+				movies m
+			WHERE
+				m.id = $2
+				AND m.deleted_at IS NULL
+			ON CONFLICT (user_id, movie_id) DO UPDATE
+			SET
+				watched_at = EXCLUDED.watched_at,
+				rewatch_count = EXCLUDED.rewatch_count
+			RETURNING
+				movie_id,
+				position,
+				added_at,
+				updated_at
+		)
+		SELECT
+			a.position,
+			a.added_at,
+			a.updated_at,
+			m.title,
+			m.release_date
+		FROM
+			added a
+			JOIN movies m ON m.id = a.movie_id
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockUser(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, query, userID, entry.MovieID, entry.WatchedAt, entry.RewatchCount).Scan(
+		&entry.Position,
+		&entry.AddedAt,
+		&entry.UpdatedAt,
+		&entry.Title,
+		&entry.ReleaseDate,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.NotFound("movie", entry.MovieID)
+		}
+		return fmt.Errorf("failed to add movie to history: %w", repository.Error(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit history entry: %v", err)
+	}
+
+	return nil
+}
+
+func (r *watchlistPostgresRepo) RemoveFromHistory(ctx context.Context, userID, movieID int) error {
+	return r.remove(ctx, "watch_history", "history", userID, movieID)
+}
+
+func (r *watchlistPostgresRepo) ListHistory(ctx context.Context, userID int) ([]*models.HistoryEntry, error) {
+	query := `
+		SELECT
+			h.movie_id,
+			m.title,
+			m.release_date,
+			h.position,
+			h.watched_at,
+			h.rewatch_count,
+			h.added_at,
+			h.updated_at
+		FROM
+			watch_history h
+			JOIN movies m ON m.id = h.movie_id
+		WHERE
+			h.user_id = $1
+			AND m.deleted_at IS NULL
+		ORDER BY
+			h.position,
+			h.movie_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %v", err)
+	}
+	defer rows.Close()
+
+	entries := []*models.HistoryEntry{}
+	for rows.Next() {
+		var (
+			entry     models.HistoryEntry
+			watchedAt sql.NullTime
+		)
+
+		err := rows.Scan(
+			&entry.MovieID,
+			&entry.Title,
+			&entry.ReleaseDate,
+			&entry.Position,
+			&watchedAt,
+			&entry.RewatchCount,
+			&entry.AddedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan history entry: %v", err)
+		}
+
+		if watchedAt.Valid {
+			entry.WatchedAt = &watchedAt.Time
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list history: %v", err)
+	}
+
+	return entries, nil
+}
+
+func (r *watchlistPostgresRepo) ReorderHistory(ctx context.Context, userID int, movieIDs []int, check func([]int) error) error {
+	return r.reorder(ctx, "watch_history", userID, movieIDs, check)
+}
+
+// remove deletes entry of visible movie, table is one of constant table names and never comes from input
+func (r *watchlistPostgresRepo) remove(ctx context.Context, table, list string, userID, movieID int) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s t
+		USING
+			movies m
+		WHERE
+			m.id = t.movie_id
+			AND m.deleted_at IS NULL
+			AND t.user_id = $1
+			AND t.movie_id = $2
+	`, table)
+
+	result, err := r.db.ExecContext(ctx, query, userID, movieID)
+	if err != nil {
+		return fmt.Errorf("failed to remove movie from %s: %v", list, err)
+	}
+
+	return repository.CheckAffected(result, "movie in "+list+" of user", movieID)
+}
+
+// reorder sets positions of movies to their indexes in movieIDs once check accepts visible movies of the list,
+// table is one of constant table names and never comes from input
+func (r *watchlistPostgresRepo) reorder(ctx context.Context, table string, userID int, movieIDs []int, check func([]int) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// adds lock the user too, so no entry can be added until commit
+	if err := lockUser(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	// entries are locked too, so none can be removed until commit
+	query := fmt.Sprintf(`
+		SELECT
+			t.movie_id
+		FROM
+			%s t
+			JOIN movies m ON m.id = t.movie_id
+		WHERE
+			t.user_id = $1
+			AND m.deleted_at IS NULL
+		FOR UPDATE OF t
+	`, table)
+
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	current := []int{}
+	for rows.Next() {
+		var movieID int
+		if err := rows.Scan(&movieID); err != nil {
+			return fmt.Errorf("failed to scan movie id: %v", err)
+		}
+		current = append(current, movieID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to lock %s: %v", table, err)
+	}
+
+	if err := check(current); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`
+		UPDATE
+			%s t
+		SET
+			position = o.ordinality - 1
+		FROM
+			UNNEST($2::INT[]) WITH ORDINALITY AS o (movie_id, ordinality)
+		WHERE
+			t.user_id = $1
+			AND t.movie_id = o.movie_id
+	`, table)
+
+	if _, err := tx.ExecContext(ctx, query, userID, pq.Array(movieIDs)); err != nil {
+		return fmt.Errorf("failed to reorder %s: %v", table, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s order: %v", table, err)
+	}
+
+	return nil
+}
+
+// lockUser locks the user row until tx ends, adds and reorders of user's lists take it one after another
+// so positions computed from MAX(position) don't collide
+func lockUser(ctx context.Context, tx *sql.Tx, userID int) error {
+	var id int
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			id
+		FROM
+			users
+		WHERE
+			id = $1
+		FOR UPDATE
+	`, userID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.NotFound("user", userID)
+		}
+		return fmt.Errorf("failed to lock user: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/apikey"
+)
+
+const auditAPIKey = "api_key"
+
+// APIKeyService interface describes structs that are used for creating API key handlers,
+// it also authenticates machine clients by their keys
+type APIKeyService interface {
+	auth.KeyAuthenticator
+	// CreateAPIKey generates the key, its secret is set into key.Key only this once
+	CreateAPIKey(context.Context, *models.APIKey) error
+	ListAPIKeys(context.Context) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) error
+}
+
+type apiKeyService struct {
+	repo apikey.Repository
+}
+
+// NewAPIKeyService creates new instance of APIKeyService interface
+func NewAPIKeyService(r apikey.Repository) APIKeyService {
+	return &apiKeyService{repo: r}
+}
+
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	if err := auth.Authorize(ctx, auth.ActionManageKeys); err != nil {
+		return err
+	}
+
+	if err := validateAPIKey(key); err != nil {
+		return err
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	key.CreatedBy = principal.Subject
+	key.Key, key.Prefix = auth.NewAPIKey()
+	key.Hash = auth.HashSecret(key.Key)
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+
+	if err := s.repo.Create(ctx, key); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditAPIKey, key.ID)
+
+	return nil
+}
+
+func (s *apiKeyService) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	if err := auth.Authorize(ctx, auth.ActionManageKeys); err != nil {
+		return nil, err
+	}
+
+	return s.repo.List(ctx)
+}
+
+// RevokeAPIKey disables the key for good, revoked keys stay in the list
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	if err := auth.Authorize(ctx, auth.ActionManageKeys); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditAPIKey, id)
+
+	return s.repo.Revoke(ctx, id)
+}
+
+// AuthenticateAPIKey returns principal of active key, it has the least role its scopes need
+func (s *apiKeyService) AuthenticateAPIKey(ctx context.Context, secret string) (*auth.Principal, error) {
+	key, err := s.repo.Use(ctx, auth.HashSecret(secret))
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("%w: api key is invalid, expired or revoked", models.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	principal := &auth.Principal{
+		Subject: "apikey:" + key.Prefix,
+		Role:    auth.ScopesRole(key.Scopes),
+		Scopes:  key.Scopes,
+	}
+
+	return principal, nil
+}
+
+func validateAPIKey(key *models.APIKey) error {
+	v := &ValidationError{}
+
+	if key.Name == "" {
+		v.Add("name", CodeRequired, "name is required")
+	}
+
+	if len(key.Scopes) == 0 {
+		v.Add("scopes", CodeRequired, "at least one scope is required")
+	}
+
+	for _, scope := range key.Scopes {
+		if !auth.ValidScope(scope) {
+			v.Add("scopes", CodeInvalid, fmt.Sprintf("unknown scope %q", scope))
+		}
+	}
+
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		v.Add("expires_at", CodeInvalid, "expiry must be in the future")
+	}
+
+	return v.OrNil()
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/audit"
+)
+
+// AuditService interface describes structs that are used for creating audit handlers
+type AuditService interface {
+	ListAudit(context.Context, *models.AuditQuery) (*models.AuditList, error)
+}
+
+type auditService struct {
+	repo audit.Repository
+}
+
+// NewAuditService creates new instance of AuditService interface
+func NewAuditService(r audit.Repository) AuditService {
+	return &auditService{repo: r}
+}
+
+func (s *auditService) ListAudit(ctx context.Context, q *models.AuditQuery) (*models.AuditList, error) {
+	if err := auth.Authorize(ctx, auth.ActionReadAudit); err != nil {
+		return nil, err
+	}
+
+	q.Limit, q.Offset = normalizePage(q.Limit, q.Offset)
+
+	entries, total, err := s.repo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuditList{
+		Entries: entries,
+		Total:   total,
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+	}, nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/collection"
+)
+
+const auditCollection = "collection"
+
+// CollectionService interface describes structs that are used for creating collection handlers.
+// Editors curate collections, private ones are hidden from everyone else
+type CollectionService interface {
+	CreateCollection(context.Context, *models.Collection) error
+	GetCollection(context.Context, string) (*models.Collection, error)
+	UpdateCollection(ctx context.Context, slug string, collection *models.Collection) (*models.Collection, error)
+	DeleteCollection(context.Context, string) error
+	ListCollections(context.Context, *models.CollectionQuery) (*models.CollectionList, error)
+	ListMovieCollections(ctx context.Context, movieID int) ([]*models.Collection, error)
+	ListCollectionMovies(ctx context.Context, slug string) ([]*models.CollectionMovie, error)
+	AddCollectionMovie(ctx context.Context, slug string, placement *models.CollectionPlacement) (*models.CollectionMovie, error)
+	// MoveCollectionMovie puts member of collection at index of placement, index beyond the end moves it to the end
+	MoveCollectionMovie(ctx context.Context, slug string, placement *models.CollectionPlacement) (*models.CollectionMovie, error)
+	RemoveCollectionMovie(ctx context.Context, slug string, movieID int) error
+}
+
+type collectionService struct {
+	repo collection.Repository
+}
+
+// NewCollectionService creates new instance of CollectionService interface
+func NewCollectionService(r collection.Repository) CollectionService {
+	return &collectionService{repo: r}
+}
+
+func (s *collectionService) CreateCollection(ctx context.Context, c *models.Collection) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	if err := validateCollection(c); err != nil {
+		return err
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	c.CreatedBy = principal.Subject
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditCollection, c.ID)
+
+	return nil
+}
+
+func (s *collectionService) GetCollection(ctx context.Context, slug string) (*models.Collection, error) {
+	c, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.Public && !canSeePrivate(ctx) {
+		return nil, fmt.Errorf("collection %s: %w", slug, models.ErrNotFound)
+	}
+
+	return c, nil
+}
+
+func (s *collectionService) UpdateCollection(ctx context.Context, slug string, c *models.Collection) (*models.Collection, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if c.Slug == "" {
+		c.Slug = slug
+	}
+
+	if err := validateCollection(c); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, slug, c)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetEntity(ctx, auditCollection, updated.ID)
+
+	return updated, nil
+}
+
+func (s *collectionService) DeleteCollection(ctx context.Context, slug string) error {
+	if err := auth.Authorize(ctx, auth.ActionDelete); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditCollection, slug)
+
+	return s.repo.Delete(ctx, slug)
+}
+
+func (s *collectionService) ListCollections(ctx context.Context, q *models.CollectionQuery) (*models.CollectionList, error) {
+	q.Limit, q.Offset = normalizePage(q.Limit, q.Offset)
+	q.IncludePrivate = canSeePrivate(ctx)
+
+	collections, total, err := s.repo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CollectionList{
+		Collections: collections,
+		Total:       total,
+		Limit:       q.Limit,
+		Offset:      q.Offset,
+	}, nil
+}
+
+func (s *collectionService) ListMovieCollections(ctx context.Context, movieID int) ([]*models.Collection, error) {
+	return s.repo.ListByMovie(ctx, movieID, canSeePrivate(ctx))
+}
+
+func (s *collectionService) ListCollectionMovies(ctx context.Context, slug string) ([]*models.CollectionMovie, error) {
+	c, err := s.GetCollection(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListMovies(ctx, c.ID)
+}
+
+func (s *collectionService) AddCollectionMovie(ctx context.Context, slug string, p *models.CollectionPlacement) (*models.CollectionMovie, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if err := validatePlacement(p.Index); err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetEntity(ctx, auditCollection, c.ID)
+
+	return s.repo.AddMovie(ctx, c.ID, p)
+}
+
+func (s *collectionService) MoveCollectionMovie(ctx context.Context, slug string, p *models.CollectionPlacement) (*models.CollectionMovie, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if p.Index == nil {
+		v := &ValidationError{}
+		v.Add("index", CodeRequired, "index is required")
+		return nil, v
+	}
+
+	if err := validatePlacement(p.Index); err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetEntity(ctx, auditCollection, c.ID)
+
+	return s.repo.MoveMovie(ctx, c.ID, p.MovieID, *p.Index)
+}
+
+func (s *collectionService) RemoveCollectionMovie(ctx context.Context, slug string, movieID int) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	c, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditCollection, c.ID)
+
+	return s.repo.RemoveMovie(ctx, c.ID, movieID)
+}
+
+// canSeePrivate reports whether caller may see private collections
+func canSeePrivate(ctx context.Context) bool {
+	return auth.Authorize(ctx, auth.ActionEdit) == nil
+}
+
+func validateCollection(c *models.Collection) error {
+	v := &ValidationError{}
+
+	if !slugPattern.MatchString(c.Slug) {
+		v.Add("slug", CodeInvalid, "slug must be lowercase letters and digits separated by dashes")
+	}
+
+	validateText(v, "name", c.Name, MaxNameLength, true)
+	validateText(v, "description", c.Description, MaxDescriptionLength, false)
+	validateText(v, "cover_url", c.CoverURL, MaxURLLength, false)
+
+	if c.CoverURL != "" {
+		if u, err := url.Parse(c.CoverURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.Add("cover_url", CodeInvalid, "cover url must be absolute http or https url")
+		}
+	}
+
+	return v.OrNil()
+}
+
+func validatePlacement(index *int) error {
+	if index != nil && *index < 0 {
+		v := &ValidationError{}
+		v.Add("index", CodeOutOfRange, "index can't be negative")
+		return v
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+// ErrInvalidCursor is returned when pagination cursor is malformed, tampered or issued for another sort order
+var ErrInvalidCursor = fmt.Errorf("invalid cursor: %w", models.ErrBadRequest)
+
+type cursorPayload struct {
+	SortBy   string `json:"s"`
+	SortDesc bool   `json:"d"`
+	Value    string `json:"v"`
+	ID       int    `json:"i"`
+}
+
+// cursorCodec encodes keyset positions into opaque tokens signed with HMAC-SHA256
+type cursorCodec struct {
+	secret []byte
+}
+
+func (c cursorCodec) encode(p cursorPayload) string {
+	data, _ := json.Marshal(p)
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(c.sign(encoded))
+}
+
+func (c cursorCodec) decode(token string) (*cursorPayload, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, c.sign(encoded)) {
+		return nil, ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &p, nil
+}
+
+func (c cursorCodec) sign(data string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// sortValue returns value of the movie field which list is sorted by
+func sortValue(movie *models.Movie, sortBy string) string {
+	switch sortBy {
+	case models.SortByTitle:
+		return movie.Title
+	case models.SortByReleaseDate:
+		return movie.ReleaseDate.UTC().Format(time.RFC3339Nano)
+	case models.SortByCreatedAt:
+		return movie.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case models.SortByRating:
+		return strconv.FormatFloat(movie.Rating.Average, 'f', -1, 64)
+	default:
+		return movie.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	payload := cursorPayload{SortBy: "title", SortDesc: true, Value: "Heat", ID: 42}
+
+	decoded, err := codec.decode(codec.encode(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *decoded != payload {
+		t.Errorf("Expected %+v, got %+v", payload, *decoded)
+	}
+}
+
+func TestCursorCodec_Tampered(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	token := codec.encode(cursorPayload{SortBy: "title", Value: "Heat", ID: 42})
+
+	other := cursorCodec{secret: []byte("other secret")}
+	forged := other.encode(cursorPayload{SortBy: "title", Value: "Heat", ID: 1})
+
+	for _, cursor := range []string{"", "abc", token + "x", forged} {
+		if _, err := codec.decode(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", cursor, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/franchise"
+)
+
+const auditFranchise = "franchise"
+
+// FranchiseService interface describes structs that are used for creating franchise handlers
+type FranchiseService interface {
+	CreateFranchise(context.Context, *models.Franchise) error
+	GetFranchise(context.Context, string) (*models.Franchise, error)
+	UpdateFranchise(ctx context.Context, slug string, franchise *models.Franchise) (*models.Franchise, error)
+	DeleteFranchise(context.Context, string) error
+	ListFranchises(context.Context, *models.FranchiseQuery) (*models.FranchiseList, error)
+	// SetFranchiseMovies replaces members of franchise, empty list removes all of them
+	SetFranchiseMovies(ctx context.Context, slug string, members *models.FranchiseMembers) ([]*models.FranchiseMovie, error)
+}
+
+type franchiseService struct {
+	repo franchise.Repository
+}
+
+// NewFranchiseService creates new instance of FranchiseService interface
+func NewFranchiseService(r franchise.Repository) FranchiseService {
+	return &franchiseService{repo: r}
+}
+
+func (s *franchiseService) CreateFranchise(ctx context.Context, f *models.Franchise) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	if err := validateFranchise(f); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, f); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditFranchise, f.ID)
+
+	return nil
+}
+
+func (s *franchiseService) GetFranchise(ctx context.Context, slug string) (*models.Franchise, error) {
+	return s.repo.GetBySlug(ctx, slug)
+}
+
+func (s *franchiseService) UpdateFranchise(ctx context.Context, slug string, f *models.Franchise) (*models.Franchise, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if f.Slug == "" {
+		f.Slug = slug
+	}
+
+	if err := validateFranchise(f); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, slug, f)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetEntity(ctx, auditFranchise, updated.ID)
+
+	return updated, nil
+}
+
+func (s *franchiseService) DeleteFranchise(ctx context.Context, slug string) error {
+	if err := auth.Authorize(ctx, auth.ActionDelete); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditFranchise, slug)
+
+	return s.repo.Delete(ctx, slug)
+}
+
+func (s *franchiseService) ListFranchises(ctx context.Context, q *models.FranchiseQuery) (*models.FranchiseList, error) {
+	q.Limit, q.Offset = normalizePage(q.Limit, q.Offset)
+
+	franchises, total, err := s.repo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.FranchiseList{
+		Franchises: franchises,
+		Total:      total,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+	}, nil
+}
+
+func (s *franchiseService) SetFranchiseMovies(ctx context.Context, slug string, m *models.FranchiseMembers) ([]*models.FranchiseMovie, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if err := validateFranchiseMembers(m); err != nil {
+		return nil, err
+	}
+
+	f, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetEntity(ctx, auditFranchise, f.ID)
+
+	if m.MovieIDs == nil {
+		m.MovieIDs = []int{}
+	}
+
+	return s.repo.SetMovies(ctx, f.ID, m.MovieIDs)
+}
+
+func validateFranchise(f *models.Franchise) error {
+	v := &ValidationError{}
+
+	if !slugPattern.MatchString(f.Slug) {
+		v.Add("slug", CodeInvalid, "slug must be lowercase letters and digits separated by dashes")
+	}
+
+	validateText(v, "name", f.Name, MaxNameLength, true)
+	validateText(v, "description", f.Description, MaxDescriptionLength, false)
+
+	return v.OrNil()
+}
+
+func validateFranchiseMembers(m *models.FranchiseMembers) error {
+	v := &ValidationError{}
+
+	if len(m.MovieIDs) > MaxFranchiseMovies {
+		v.Add("movie_ids", CodeTooMany, fmt.Sprintf("at most %d movies are allowed", MaxFranchiseMovies))
+	}
+
+	seen := make(map[int]bool, len(m.MovieIDs))
+	for _, id := range m.MovieIDs {
+		if id <= 0 || seen[id] {
+			v.Add("movie_ids", CodeInvalid, "movie ids must be positive and unique")
+			break
+		}
+		seen[id] = true
+	}
+
+	return v.OrNil()
+}
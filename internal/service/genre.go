@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/genre"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// GenreService interface describes structs that are used for creating genre handlers
+type GenreService interface {
+	CreateGenre(context.Context, *models.Genre) error
+	GetGenre(context.Context, string) (*models.Genre, error)
+	UpdateGenre(ctx context.Context, slug string, genre *models.Genre) (*models.Genre, error)
+	DeleteGenre(context.Context, string) error
+	ListGenres(context.Context) ([]*models.Genre, error)
+}
+
+type genreService struct {
+	repo genre.Repository
+}
+
+// NewGenreService creates new instance of GenreService interface
+func NewGenreService(r genre.Repository) GenreService {
+	return &genreService{repo: r}
+}
+
+func (s *genreService) CreateGenre(ctx context.Context, g *models.Genre) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	if err := validateGenre(g); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, g)
+}
+
+func (s *genreService) GetGenre(ctx context.Context, slug string) (*models.Genre, error) {
+	return s.repo.GetBySlug(ctx, slug)
+}
+
+func (s *genreService) UpdateGenre(ctx context.Context, slug string, g *models.Genre) (*models.Genre, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if g.Slug == "" {
+		g.Slug = slug
+	}
+
+	if err := validateGenre(g); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(ctx, slug, g)
+}
+
+func (s *genreService) DeleteGenre(ctx context.Context, slug string) error {
+	if err := auth.Authorize(ctx, auth.ActionDelete); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, slug)
+}
+
+func (s *genreService) ListGenres(ctx context.Context) ([]*models.Genre, error) {
+	return s.repo.List(ctx)
+}
+
+func validateGenre(g *models.Genre) error {
+	v := &ValidationError{}
+
+	if !slugPattern.MatchString(g.Slug) {
+		v.Add("slug", CodeInvalid, "slug must be lowercase letters and digits separated by dashes")
+	}
+
+	validateText(v, "name", g.Name, MaxGenreLength, true)
+
+	return v.OrNil()
+}
+
+// normalizeGenres lowercases and deduplicates genre slugs keeping nil as "not set"
+func normalizeGenres(slugs []string) []string {
+	if slugs == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(slugs))
+	for _, slug := range slugs {
+		slug = strings.ToLower(strings.TrimSpace(slug))
+		if !slices.Contains(normalized, slug) {
+			normalized = append(normalized, slug)
+		}
+	}
+
+	return normalized
+}
+
+// checkGenres adds validation error for every slug which is absent in vocabulary
+func checkGenres(ctx context.Context, repo genre.Repository, v *ValidationError, slugs []string) error {
+	if len(slugs) == 0 {
+		return nil
+	}
+
+	existing, err := repo.ExistingSlugs(ctx, slugs)
+	if err != nil {
+		return err
+	}
+
+	for i, slug := range slugs {
+		if !slices.Contains(existing, slug) {
+			v.Add(fmt.Sprintf("genres[%d]", i), CodeUnknownGenre, fmt.Sprintf("genre %q does not exist", slug))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+type stubGenreRepo struct {
+	slugs []string
+}
+
+func (r *stubGenreRepo) Create(context.Context, *models.Genre) error { return nil }
+
+func (r *stubGenreRepo) GetBySlug(context.Context, string) (*models.Genre, error) { return nil, nil }
+
+func (r *stubGenreRepo) Update(context.Context, string, *models.Genre) (*models.Genre, error) {
+	return nil, nil
+}
+
+func (r *stubGenreRepo) Delete(context.Context, string) error { return nil }
+
+func (r *stubGenreRepo) List(context.Context) ([]*models.Genre, error) { return nil, nil }
+
+func (r *stubGenreRepo) ExistingSlugs(_ context.Context, slugs []string) ([]string, error) {
+	var existing []string
+	for _, slug := range slugs {
+		if slices.Contains(r.slugs, slug) {
+			existing = append(existing, slug)
+		}
+	}
+	return existing, nil
+}
+
+func TestNormalizeGenres(t *testing.T) {
+	if normalizeGenres(nil) != nil {
+		t.Error("Expected nil genres to stay nil")
+	}
+
+	got := normalizeGenres([]string{" Drama", "crime", "drama"})
+	if !slices.Equal(got, []string{"drama", "crime"}) {
+		t.Errorf("Unexpected normalized genres %v", got)
+	}
+}
+
+func TestCheckGenres(t *testing.T) {
+	repo := &stubGenreRepo{slugs: []string{"drama", "sci-fi"}}
+
+	v := &ValidationError{}
+	if err := checkGenres(t.Context(), repo, v, []string{"drama", "sci-fi"}); err != nil || v.OrNil() != nil {
+		t.Errorf("Expected known genres to pass, got %v", v)
+	}
+
+	if err := checkGenres(t.Context(), repo, v, []string{"drama", "scifi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FieldError{{Field: "genres[1]", Code: CodeUnknownGenre, Message: `genre "scifi" does not exist`}}
+	if !slices.Equal(v.Fields, expected) {
+		t.Errorf("Expected %v, got %v", expected, v.Fields)
+	}
+}
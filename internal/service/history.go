@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+// historyFields are movie fields tracked in history, read-only fields change with every revision
+// and are left out
+var historyFields = []struct {
+	name  string
+	value func(*models.Movie) any
+}{
+	{"title", func(m *models.Movie) any { return m.Title }},
+	{"director", func(m *models.Movie) any { return m.Director }},
+	{"release_date", func(m *models.Movie) any { return m.ReleaseDate }},
+	{"genre", func(m *models.Movie) any { return m.Genre }},
+	{"genres", func(m *models.Movie) any {
+		if m.Genres == nil {
+			return []string{}
+		}
+		return m.Genres
+	}},
+	{"description", func(m *models.Movie) any { return m.Description }},
+	{"tags", func(m *models.Movie) any {
+		if m.Tags == nil {
+			return []string{}
+		}
+		return m.Tags
+	}},
+	{"original_title", func(m *models.Movie) any { return m.OriginalTitle }},
+	{"runtime", func(m *models.Movie) any {
+		if m.Runtime == nil {
+			return nil
+		}
+		return *m.Runtime
+	}},
+	{"countries", func(m *models.Movie) any {
+		if m.Countries == nil {
+			return []string{}
+		}
+		return m.Countries
+	}},
+	{"languages", func(m *models.Movie) any {
+		if m.Languages == nil {
+			return []string{}
+		}
+		return m.Languages
+	}},
+	{"certifications", func(m *models.Movie) any {
+		if m.Certifications == nil {
+			return map[string]string{}
+		}
+		return m.Certifications
+	}},
+	{"budget", func(m *models.Movie) any {
+		if m.Budget == nil {
+			return nil
+		}
+		return *m.Budget
+	}},
+	{"box_office", func(m *models.Movie) any {
+		if m.BoxOffice == nil {
+			return nil
+		}
+		return *m.BoxOffice
+	}},
+	{"deleted_at", func(m *models.Movie) any {
+		if m.DeletedAt == nil {
+			return nil
+		}
+		return *m.DeletedAt
+	}},
+}
+
+// GetMovieAt returns the movie as it was at asOf
+func (s *movieService) GetMovieAt(ctx context.Context, id int, asOf time.Time) (*models.Movie, error) {
+	revision, err := s.repo.GetRevisionAt(ctx, id, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision.Snapshot.DeletedAt != nil {
+		return nil, fmt.Errorf("movie %d was in trash at %s: %w", id, asOf.Format(time.RFC3339), models.ErrNotFound)
+	}
+
+	return revision.Snapshot, nil
+}
+
+// GetMovieHistory returns revisions of the movie with changes made by each of them, newest first.
+// History of movie in trash is shown only to those who can read trash
+func (s *movieService) GetMovieHistory(ctx context.Context, id int) ([]*models.Revision, error) {
+	revisions, err := s.repo.ListRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("movie %d: %w", id, models.ErrNotFound)
+	}
+
+	if revisions[len(revisions)-1].Snapshot.DeletedAt != nil && auth.Authorize(ctx, auth.ActionReadTrash) != nil {
+		return nil, fmt.Errorf("movie %d is in trash: %w", id, models.ErrNotFound)
+	}
+
+	diffRevisions(revisions)
+	slices.Reverse(revisions)
+
+	return revisions, nil
+}
+
+// RevertMovie brings editable fields and tags of the movie back to their state at the revision,
+// revert is stored as a usual update. Non-zero version must match the stored version
+func (s *movieService) RevertMovie(ctx context.Context, id, revisionID, version int) (*models.Movie, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.repo.GetRevision(ctx, id, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	movie := revision.Snapshot
+	if err := s.validateMovie(ctx, movie); err != nil {
+		return nil, err
+	}
+
+	return s.update(ctx, id, func(current *models.Movie) error {
+		if err := checkVersion(current, version); err != nil {
+			return err
+		}
+
+		replaceMovie(current, movie)
+		// tags are read-only for PUT and PATCH, but they are part of the state revert brings back
+		current.Tags = movie.Tags
+		return nil
+	})
+}
+
+// diffRevisions fills changes of revisions ordered from the oldest one,
+// the first revision lists every field which is set
+func diffRevisions(revisions []*models.Revision) {
+	previous := &models.Movie{}
+
+	for _, revision := range revisions {
+		revision.Changes = diffMovies(previous, revision.Snapshot)
+		previous = revision.Snapshot
+	}
+}
+
+func diffMovies(old, new *models.Movie) []models.FieldChange {
+	changes := []models.FieldChange{}
+
+	for _, field := range historyFields {
+		oldValue, newValue := field.value(old), field.value(new)
+		if equalValues(oldValue, newValue) {
+			continue
+		}
+
+		changes = append(changes, models.FieldChange{Field: field.name, Old: oldValue, New: newValue})
+	}
+
+	return changes
+}
+
+// equalValues compares times by instant, as snapshots may keep them in different locations
+func equalValues(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
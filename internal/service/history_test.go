@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	released := time.Date(1995, time.December, 15, 0, 0, 0, 0, time.UTC)
+	deleted := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	revisions := []*models.Revision{
+		{Snapshot: &models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: released}},
+		// same instant in another location is not a change
+		{Snapshot: &models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: released.In(time.FixedZone("", 0)), Genres: []string{}}},
+		{Snapshot: &models.Movie{Title: "Heat", Director: "Michael Mann", ReleaseDate: released, Genres: []string{"crime"}, DeletedAt: &deleted}},
+	}
+
+	diffRevisions(revisions)
+
+	if len(revisions[0].Changes) != 3 {
+		t.Errorf("Expected first revision to list title, director and release date, got %+v", revisions[0].Changes)
+	}
+
+	if len(revisions[1].Changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", revisions[1].Changes)
+	}
+
+	changes := revisions[2].Changes
+	if len(changes) != 2 || changes[0].Field != "genres" || changes[1].Field != "deleted_at" || changes[1].Old != nil {
+		t.Errorf("Expected genres and deleted_at changes, got %+v", changes)
+	}
+}
@@ -3,8 +3,14 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
 	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/genre"
 	"github.com/CAATHARSIS/movies-library/internal/repository/movie"
 )
 
@@ -13,35 +19,335 @@ type MovieService interface {
 	CreateMovie(context.Context, *models.Movie) error
 	GetMovie(context.Context, int) (*models.Movie, error)
 	UpdateMovie(context.Context, *models.Movie) (*models.Movie, error)
-	DeleteMovie(context.Context, int) error
-	ListMovies(context.Context) ([]*models.Movie, error)
+	PatchMovie(ctx context.Context, id, version int, patchType string, patch []byte) (*models.Movie, error)
+	DeleteMovie(ctx context.Context, id, version int) error
+	ListDeletedMovies(ctx context.Context, limit, offset int) (*models.MovieList, error)
+	RestoreMovie(context.Context, int) (*models.Movie, error)
+	PurgeMovie(context.Context, int) error
+	GetMovieAt(ctx context.Context, id int, asOf time.Time) (*models.Movie, error)
+	GetMovieHistory(context.Context, int) ([]*models.Revision, error)
+	RevertMovie(ctx context.Context, id, revisionID, version int) (*models.Movie, error)
+	ListMovies(context.Context, *models.MovieQuery) (*models.MovieList, error)
+	SearchMovies(context.Context, *models.SearchQuery) (*models.SearchResults, error)
+	SuggestMovies(context.Context, string, int) ([]*models.Suggestion, error)
 }
 
+// auditMovie is entity type of movies in audit log
+const auditMovie = "movie"
+
+// Pagination limits of movies list
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+
+	DefaultSuggestions = 10
+	MaxSuggestions     = 25
+)
+
 type movieService struct {
-	repo movie.Repository
+	repo    movie.Repository
+	genres  genre.Repository
+	cursors cursorCodec
 }
 
-// NewMovieService creates new instance of MovieService interface
-func NewMovieService(r movie.Repository) MovieService {
-	return &movieService{repo: r}
+// NewMovieService creates new instance of MovieService interface,
+// cursorSecret is used for signing pagination cursors
+func NewMovieService(r movie.Repository, genres genre.Repository, cursorSecret []byte) MovieService {
+	return &movieService{repo: r, genres: genres, cursors: cursorCodec{secret: cursorSecret}}
 }
 
 func (s *movieService) CreateMovie(ctx context.Context, movie *models.Movie) error {
-	return s.repo.Create(ctx, movie)
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	if err := s.validateMovie(ctx, movie); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, movie); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditMovie, movie.ID)
+	audit.SetAfter(ctx, movie)
+
+	return nil
 }
 
 func (s *movieService) GetMovie(ctx context.Context, id int) (*models.Movie, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
+// UpdateMovie replaces every editable field of the movie, fields missing in request are cleared.
+// Non-zero movie.Version must match the stored version
 func (s *movieService) UpdateMovie(ctx context.Context, movie *models.Movie) (*models.Movie, error) {
-	return s.repo.Update(ctx, movie)
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateMovie(ctx, movie); err != nil {
+		return nil, err
+	}
+
+	return s.update(ctx, movie.ID, func(current *models.Movie) error {
+		if err := checkVersion(current, movie.Version); err != nil {
+			return err
+		}
+
+		replaceMovie(current, movie)
+		return nil
+	})
+}
+
+// PatchMovie applies merge patch or JSON patch to the movie, patchType is media type of the patch.
+// Non-zero version must match the stored version
+func (s *movieService) PatchMovie(ctx context.Context, id, version int, patchType string, patch []byte) (*models.Movie, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	return s.update(ctx, id, func(current *models.Movie) error {
+		if err := checkVersion(current, version); err != nil {
+			return err
+		}
+
+		patched, err := applyMoviePatch(current, patchType, patch)
+		if err != nil {
+			return err
+		}
+
+		if err := s.validateMovie(ctx, patched); err != nil {
+			return err
+		}
+
+		replaceMovie(current, patched)
+		return nil
+	})
+}
+
+// update runs repository update and records the change in audit entry of ctx
+func (s *movieService) update(ctx context.Context, id int, fn func(*models.Movie) error) (*models.Movie, error) {
+	audit.SetEntity(ctx, auditMovie, id)
+
+	updated, err := s.repo.Update(ctx, id, func(current *models.Movie) error {
+		audit.SetBefore(ctx, current)
+		return fn(current)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetAfter(ctx, updated)
+
+	return updated, nil
+}
+
+func (s *movieService) DeleteMovie(ctx context.Context, id, version int) error {
+	if err := auth.Authorize(ctx, auth.ActionDelete); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditMovie, id)
+
+	movie, err := s.repo.Delete(ctx, id, version)
+	if err != nil {
+		return err
+	}
+
+	audit.SetBefore(ctx, movie)
+
+	return nil
+}
+
+// ListDeletedMovies lists movies in trash, recently deleted go first
+func (s *movieService) ListDeletedMovies(ctx context.Context, limit, offset int) (*models.MovieList, error) {
+	if err := auth.Authorize(ctx, auth.ActionReadTrash); err != nil {
+		return nil, err
+	}
+
+	limit, offset = normalizePage(limit, offset)
+
+	movies, total, err := s.repo.ListDeleted(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MovieList{
+		Movies: movies,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+func (s *movieService) RestoreMovie(ctx context.Context, id int) (*models.Movie, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	audit.SetEntity(ctx, auditMovie, id)
+
+	movie, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetAfter(ctx, movie)
+
+	return movie, nil
+}
+
+// PurgeMovie deletes the movie permanently, only movies in trash can be purged
+func (s *movieService) PurgeMovie(ctx context.Context, id int) error {
+	if err := auth.Authorize(ctx, auth.ActionPurge); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditMovie, id)
+
+	movie, err := s.repo.Purge(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	audit.SetBefore(ctx, movie)
+
+	return nil
+}
+
+func (s *movieService) ListMovies(ctx context.Context, q *models.MovieQuery) (*models.MovieList, error) {
+	if q.Cursor != "" {
+		cursor, err := s.cursors.decode(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if q.SortBy == "" {
+			q.SortBy = cursor.SortBy
+			q.SortDesc = cursor.SortDesc
+		}
+
+		if q.SortBy != cursor.SortBy || q.SortDesc != cursor.SortDesc {
+			return nil, ErrInvalidCursor
+		}
+
+		q.After = &models.MovieCursor{Value: cursor.Value, ID: cursor.ID}
+		q.Offset = 0
+	}
+
+	normalizeMovieQuery(q)
+
+	// one extra row tells whether there is a next page
+	fetch := *q
+	fetch.Limit++
+
+	movies, total, err := s.repo.List(ctx, &fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.MovieList{
+		Movies: movies,
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+
+	if len(movies) > q.Limit {
+		list.Movies = movies[:q.Limit]
+		last := list.Movies[q.Limit-1]
+		list.NextCursor = s.cursors.encode(cursorPayload{
+			SortBy:   q.SortBy,
+			SortDesc: q.SortDesc,
+			Value:    sortValue(last, q.SortBy),
+			ID:       last.ID,
+		})
+	}
+
+	return list, nil
+}
+
+func (s *movieService) SearchMovies(ctx context.Context, q *models.SearchQuery) (*models.SearchResults, error) {
+	q.Limit, q.Offset = normalizePage(q.Limit, q.Offset)
+
+	results, total, err := s.repo.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SearchResults{
+		Results: results,
+		Total:   total,
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+	}, nil
+}
+
+func (s *movieService) SuggestMovies(ctx context.Context, text string, limit int) ([]*models.Suggestion, error) {
+	if limit <= 0 {
+		limit = DefaultSuggestions
+	}
+
+	if limit > MaxSuggestions {
+		limit = MaxSuggestions
+	}
+
+	return s.repo.Suggest(ctx, text, limit)
 }
 
-func (s *movieService) DeleteMovie(ctx context.Context, id int) error {
-	return s.repo.Delete(ctx, id)
+// validateMovie normalizes movie genres and ISO codes and returns *ValidationError listing every invalid field
+func (s *movieService) validateMovie(ctx context.Context, movie *models.Movie) error {
+	movie.Genres = normalizeGenres(movie.Genres)
+	normalizeMovieCodes(movie)
+
+	v := &ValidationError{}
+	validateMovieFields(v, movie)
+
+	if err := checkGenres(ctx, s.genres, v, movie.Genres); err != nil {
+		return err
+	}
+
+	return v.OrNil()
 }
 
-func (s *movieService) ListMovies(ctx context.Context) ([]*models.Movie, error) {
-	return s.repo.List(ctx)
+// checkVersion returns models.ErrPreconditionFailed when movie was changed since version was read,
+// zero version skips the check
+func checkVersion(movie *models.Movie, version int) error {
+	if version != 0 && movie.Version != version {
+		return fmt.Errorf("movie %d is at version %d, not %d: %w", movie.ID, movie.Version, version, models.ErrPreconditionFailed)
+	}
+
+	return nil
+}
+
+// normalizeMovieQuery fills unset fields of query with default values and normalizes tag names and ISO codes
+func normalizeMovieQuery(q *models.MovieQuery) {
+	q.Limit, q.Offset = normalizePage(q.Limit, q.Offset)
+	q.Tags = normalizeTags(q.Tags)
+	q.Country = strings.ToUpper(q.Country)
+	q.CertificationCountry = strings.ToUpper(q.CertificationCountry)
+	q.Currency = strings.ToUpper(q.Currency)
+	q.Language = strings.ToLower(q.Language)
+
+	if q.SortBy == "" {
+		q.SortBy = models.SortByUpdatedAt
+		q.SortDesc = true
+	}
+}
+
+func normalizePage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
 }
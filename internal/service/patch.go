@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/pkg/jsonpatch"
+)
+
+// Media types of supported patch documents
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// applyMoviePatch returns copy of movie with patch applied, movie itself is left untouched.
+// Read-only fields such as ID and timestamps keep their values whatever patch says
+func applyMoviePatch(movie *models.Movie, patchType string, patch []byte) (*models.Movie, error) {
+	doc, err := json.Marshal(movie)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode movie: %v", err)
+	}
+
+	switch patchType {
+	case MergePatchType:
+		doc, err = jsonpatch.MergePatch(doc, patch)
+	case JSONPatchType:
+		doc, err = jsonpatch.Apply(doc, patch)
+	default:
+		return nil, fmt.Errorf("%w: %q, use %s or %s", models.ErrUnsupportedMediaType, patchType, MergePatchType, JSONPatchType)
+	}
+
+	switch {
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		return nil, fmt.Errorf("%w: %v", models.ErrConflict, err)
+	case errors.Is(err, jsonpatch.ErrPathNotFound):
+		return nil, fmt.Errorf("%w: %v", models.ErrValidation, err)
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", models.ErrBadRequest, err)
+	}
+
+	var patched models.Movie
+
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return nil, fmt.Errorf("%w: patched movie is invalid: %v", models.ErrValidation, err)
+	}
+
+	patched.ID = movie.ID
+	patched.CreatedAt = movie.CreatedAt
+	patched.UpdatedAt = movie.UpdatedAt
+	patched.Version = movie.Version
+	patched.DeletedAt = movie.DeletedAt
+	patched.Rating = movie.Rating
+	patched.Tags = movie.Tags
+
+	return &patched, nil
+}
+
+// replaceMovie copies editable fields of src into dst
+func replaceMovie(dst, src *models.Movie) {
+	dst.Title = src.Title
+	dst.Director = src.Director
+	dst.ReleaseDate = src.ReleaseDate
+	dst.Genre = src.Genre
+	dst.Genres = src.Genres
+	dst.Description = src.Description
+	dst.OriginalTitle = src.OriginalTitle
+	dst.Runtime = src.Runtime
+	dst.Countries = src.Countries
+	dst.Languages = src.Languages
+	dst.Certifications = src.Certifications
+	dst.Budget = src.Budget
+	dst.BoxOffice = src.BoxOffice
+}
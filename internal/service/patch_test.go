@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+func TestApplyMoviePatch_KeepsReadOnlyFields(t *testing.T) {
+	created := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	movie := &models.Movie{ID: 7, Title: "Heat", Description: "LA crime saga", CreatedAt: created, UpdatedAt: created}
+
+	patched, err := applyMoviePatch(movie, MergePatchType, []byte(`{"id": 8, "created_at": "2000-01-01T00:00:00Z", "description": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if patched.ID != 7 || !patched.CreatedAt.Equal(created) {
+		t.Errorf("Expected id and created_at to be kept, got %+v", patched)
+	}
+
+	if patched.Description != "" || movie.Description != "LA crime saga" {
+		t.Errorf("Expected description to be cleared only in the copy, got %q and %q", patched.Description, movie.Description)
+	}
+}
+
+func TestApplyMoviePatch_Errors(t *testing.T) {
+	movie := &models.Movie{ID: 7, Title: "Heat"}
+
+	tests := []struct {
+		patchType string
+		patch     string
+		expected  error
+	}{
+		{"application/json", `{"title": "Ronin"}`, models.ErrUnsupportedMediaType},
+		{MergePatchType, `{"title":`, models.ErrBadRequest},
+		{MergePatchType, `{"budget": 5}`, models.ErrValidation},
+		{MergePatchType, `{"title": 5}`, models.ErrValidation},
+		{JSONPatchType, `[{"op": "test", "path": "/title", "value": "Ronin"}]`, models.ErrConflict},
+		{JSONPatchType, `[{"op": "replace", "path": "/budget", "value": 5}]`, models.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		if _, err := applyMoviePatch(movie, tt.patchType, []byte(tt.patch)); !errors.Is(err, tt.expected) {
+			t.Errorf("applyMoviePatch(%s, %s) error = %v, expected %v", tt.patchType, tt.patch, err, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/person"
+)
+
+// PersonService interface describes structs that are used for creating people handlers
+type PersonService interface {
+	CreatePerson(context.Context, *models.Person) error
+	GetPerson(context.Context, int) (*models.Person, error)
+	UpdatePerson(context.Context, *models.Person) (*models.Person, error)
+	DeletePerson(context.Context, int) error
+	ListPeople(context.Context, *models.PersonQuery) (*models.PersonList, error)
+	GetFilmography(context.Context, int) ([]*models.Credit, error)
+	AddCredit(context.Context, *models.Credit) error
+	DeleteCredit(ctx context.Context, movieID, creditID int) error
+	ListMovieCredits(context.Context, int) ([]*models.Credit, error)
+}
+
+var creditRoles = map[string]bool{
+	models.CreditDirector: true,
+	models.CreditWriter:   true,
+	models.CreditActor:    true,
+	models.CreditComposer: true,
+}
+
+type personService struct {
+	repo person.Repository
+}
+
+// NewPersonService creates new instance of PersonService interface
+func NewPersonService(r person.Repository) PersonService {
+	return &personService{repo: r}
+}
+
+func (s *personService) CreatePerson(ctx context.Context, p *models.Person) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	if err := validatePerson(p); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, p)
+}
+
+func (s *personService) GetPerson(ctx context.Context, id int) (*models.Person, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *personService) UpdatePerson(ctx context.Context, p *models.Person) (*models.Person, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if err := validatePerson(p); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(ctx, p)
+}
+
+func (s *personService) DeletePerson(ctx context.Context, id int) error {
+	if err := auth.Authorize(ctx, auth.ActionDelete); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *personService) ListPeople(ctx context.Context, q *models.PersonQuery) (*models.PersonList, error) {
+	q.Limit, q.Offset = normalizePage(q.Limit, q.Offset)
+
+	people, total, err := s.repo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PersonList{
+		People: people,
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}, nil
+}
+
+func (s *personService) GetFilmography(ctx context.Context, personID int) ([]*models.Credit, error) {
+	if _, err := s.repo.GetByID(ctx, personID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListPersonCredits(ctx, personID)
+}
+
+func (s *personService) AddCredit(ctx context.Context, credit *models.Credit) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	if !creditRoles[credit.Role] {
+		v := &ValidationError{}
+		v.Add("role", CodeInvalid, "role must be one of director, writer, actor, composer")
+		return v
+	}
+
+	if credit.Role != models.CreditActor {
+		credit.CharacterName = ""
+	}
+
+	return s.repo.AddCredit(ctx, credit)
+}
+
+func (s *personService) DeleteCredit(ctx context.Context, movieID, creditID int) error {
+	if err := auth.Authorize(ctx, auth.ActionDelete); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteCredit(ctx, movieID, creditID)
+}
+
+func (s *personService) ListMovieCredits(ctx context.Context, movieID int) ([]*models.Credit, error) {
+	return s.repo.ListMovieCredits(ctx, movieID)
+}
+
+func validatePerson(p *models.Person) error {
+	v := &ValidationError{}
+
+	validateText(v, "name", p.Name, MaxNameLength, true)
+	validateText(v, "bio", p.Bio, MaxDescriptionLength, false)
+
+	return v.OrNil()
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/rating"
+)
+
+// RatingService interface describes structs that are used for creating rating handlers,
+// every method works with rating of the current user
+type RatingService interface {
+	RateMovie(ctx context.Context, movieID, score int) (*models.RatingResult, error)
+	GetRating(ctx context.Context, movieID int) (*models.Rating, error)
+	DeleteRating(ctx context.Context, movieID int) error
+}
+
+type ratingService struct {
+	repo rating.Repository
+}
+
+// NewRatingService creates new instance of RatingService interface
+func NewRatingService(r rating.Repository) RatingService {
+	return &ratingService{repo: r}
+}
+
+func (s *ratingService) RateMovie(ctx context.Context, movieID, score int) (*models.RatingResult, error) {
+	userID, err := auth.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if score < models.MinRatingScore || score > models.MaxRatingScore {
+		v := &ValidationError{}
+		v.Add("score", CodeOutOfRange, fmt.Sprintf("score must be between %d and %d", models.MinRatingScore, models.MaxRatingScore))
+		return nil, v
+	}
+
+	audit.SetEntity(ctx, auditMovie, movieID)
+
+	r := &models.Rating{UserID: userID, MovieID: movieID, Score: score}
+	if err := s.repo.Set(ctx, r); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.Stats(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RatingResult{Rating: r, Stats: stats}, nil
+}
+
+func (s *ratingService) GetRating(ctx context.Context, movieID int) (*models.Rating, error) {
+	userID, err := auth.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Get(ctx, userID, movieID)
+}
+
+func (s *ratingService) DeleteRating(ctx context.Context, movieID int) error {
+	userID, err := auth.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditMovie, movieID)
+
+	return s.repo.Delete(ctx, userID, movieID)
+}
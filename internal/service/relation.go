@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/franchise"
+	"github.com/CAATHARSIS/movies-library/internal/repository/relation"
+)
+
+// RelationService interface describes structs that are used for creating handlers of relations between movies
+type RelationService interface {
+	CreateRelation(context.Context, *models.Relation) error
+	DeleteRelation(ctx context.Context, movieID, relatedID int) error
+	// GetRelatedMovies returns movies related to movie grouped by relation and franchises of the movie
+	GetRelatedMovies(ctx context.Context, movieID int) (*models.RelatedMovies, error)
+}
+
+var relationTypes = map[string]bool{
+	models.RelationSequel:  true,
+	models.RelationPrequel: true,
+	models.RelationRemake:  true,
+	models.RelationSpinOff: true,
+}
+
+type relationService struct {
+	repo       relation.Repository
+	franchises franchise.Repository
+}
+
+// NewRelationService creates new instance of RelationService interface
+func NewRelationService(r relation.Repository, f franchise.Repository) RelationService {
+	return &relationService{repo: r, franchises: f}
+}
+
+func (s *relationService) CreateRelation(ctx context.Context, rel *models.Relation) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	v := &ValidationError{}
+
+	if !relationTypes[rel.Type] {
+		v.Add("type", CodeInvalid, "type must be one of sequel, prequel, remake, spin_off")
+	}
+
+	switch {
+	case rel.RelatedID == 0:
+		v.Add("related_id", CodeRequired, "related_id is required")
+	case rel.RelatedID == rel.MovieID:
+		v.Add("related_id", CodeInvalid, "movie can't be related to itself")
+	}
+
+	if err := v.OrNil(); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditMovie, rel.MovieID)
+
+	return s.repo.Create(ctx, rel)
+}
+
+func (s *relationService) DeleteRelation(ctx context.Context, movieID, relatedID int) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditMovie, movieID)
+
+	return s.repo.Delete(ctx, movieID, relatedID)
+}
+
+func (s *relationService) GetRelatedMovies(ctx context.Context, movieID int) (*models.RelatedMovies, error) {
+	related, err := s.repo.ListRelated(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	franchises, err := s.franchises.ListByMovie(ctx, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RelatedMovies{
+		MovieID:    movieID,
+		Relations:  groupRelated(related),
+		Franchises: franchises,
+	}, nil
+}
+
+// groupRelated groups movies by relation keeping their order, groups without movies are omitted
+func groupRelated(related []*models.RelatedMovie) map[string][]*models.RelatedMovie {
+	groups := map[string][]*models.RelatedMovie{}
+	for _, m := range related {
+		groups[m.Group()] = append(groups[m.Group()], m)
+	}
+
+	return groups
+}
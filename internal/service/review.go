@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/review"
+)
+
+const auditReview = "review"
+
+// ReviewService interface describes structs that are used for creating review handlers.
+// Reviews are written by local users and shown to everyone once moderator approves them
+type ReviewService interface {
+	CreateReview(context.Context, *models.Review) error
+	// GetReview returns approved review, other reviews are visible only to their authors and moderators
+	GetReview(ctx context.Context, id int) (*models.Review, error)
+	UpdateReview(context.Context, *models.Review) (*models.Review, error)
+	DeleteReview(ctx context.Context, id int) error
+	// ListMovieReviews lists approved reviews of the movie
+	ListMovieReviews(context.Context, *models.ReviewQuery) (*models.ReviewList, error)
+	// ListModerationQueue lists reviews of any status for moderators, pending ones by default
+	ListModerationQueue(context.Context, *models.ReviewQuery) (*models.ReviewList, error)
+	ModerateReview(ctx context.Context, id int, moderation *models.Moderation) (*models.Review, error)
+	VoteReview(ctx context.Context, id int, vote *models.ReviewVote) error
+	DeleteReviewVote(ctx context.Context, id int) error
+}
+
+// reviewTransitions lists statuses which review can be moved to by moderator
+var reviewTransitions = map[string][]string{
+	models.ReviewPending:  {models.ReviewApproved, models.ReviewRejected},
+	models.ReviewApproved: {models.ReviewRejected},
+	models.ReviewRejected: {models.ReviewApproved},
+}
+
+type reviewService struct {
+	repo review.Repository
+}
+
+// NewReviewService creates new instance of ReviewService interface
+func NewReviewService(r review.Repository) ReviewService {
+	return &reviewService{repo: r}
+}
+
+func (s *reviewService) CreateReview(ctx context.Context, r *models.Review) error {
+	userID, err := auth.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := validateReview(r); err != nil {
+		return err
+	}
+
+	r.UserID = userID
+	r.Status = models.ReviewPending
+	r.ModeratedBy, r.ModerationNote, r.ModeratedAt = "", "", nil
+	r.HelpfulCount, r.UnhelpfulCount = 0, 0
+
+	if err := s.repo.Create(ctx, r); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditReview, r.ID)
+
+	return nil
+}
+
+func (s *reviewService) GetReview(ctx context.Context, id int) (*models.Review, error) {
+	r, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Status == models.ReviewApproved || s.isAuthor(ctx, r) || auth.Authorize(ctx, auth.ActionModerate) == nil {
+		return r, nil
+	}
+
+	return nil, fmt.Errorf("review %d: %w", id, models.ErrNotFound)
+}
+
+// UpdateReview changes text of the review, edited review needs approval again
+func (s *reviewService) UpdateReview(ctx context.Context, r *models.Review) (*models.Review, error) {
+	if _, err := s.authorOf(ctx, r.ID); err != nil {
+		return nil, err
+	}
+
+	if err := validateReview(r); err != nil {
+		return nil, err
+	}
+
+	audit.SetEntity(ctx, auditReview, r.ID)
+
+	return s.repo.Update(ctx, r)
+}
+
+func (s *reviewService) DeleteReview(ctx context.Context, id int) error {
+	if _, err := s.authorOf(ctx, id); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditReview, id)
+
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *reviewService) ListMovieReviews(ctx context.Context, q *models.ReviewQuery) (*models.ReviewList, error) {
+	q.Status = models.ReviewApproved
+
+	return s.listReviews(ctx, q)
+}
+
+func (s *reviewService) ListModerationQueue(ctx context.Context, q *models.ReviewQuery) (*models.ReviewList, error) {
+	if err := auth.Authorize(ctx, auth.ActionModerate); err != nil {
+		return nil, err
+	}
+
+	if q.Status == "" {
+		q.Status = models.ReviewPending
+	}
+
+	if _, ok := reviewTransitions[q.Status]; !ok {
+		return nil, fmt.Errorf("%w: unknown review status %q", models.ErrBadRequest, q.Status)
+	}
+
+	return s.listReviews(ctx, q)
+}
+
+// ModerateReview moves review to another status, moves which aren't allowed by reviewTransitions
+// fail with models.ErrConflict
+func (s *reviewService) ModerateReview(ctx context.Context, id int, m *models.Moderation) (*models.Review, error) {
+	if err := auth.Authorize(ctx, auth.ActionModerate); err != nil {
+		return nil, err
+	}
+
+	v := &ValidationError{}
+	if m.Status != models.ReviewApproved && m.Status != models.ReviewRejected {
+		v.Add("status", CodeInvalid, fmt.Sprintf("status must be %s or %s", models.ReviewApproved, models.ReviewRejected))
+	}
+	validateText(v, "note", m.Note, MaxDescriptionLength, false)
+	if err := v.OrNil(); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(reviewTransitions[current.Status], m.Status) {
+		return nil, fmt.Errorf("%w: review %d can't be moved from %s to %s", models.ErrConflict, id, current.Status, m.Status)
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	audit.SetEntity(ctx, auditReview, id)
+
+	return s.repo.Moderate(ctx, id, current.Status, m, principal.Subject)
+}
+
+// VoteReview records whether user found approved review helpful, authors can't vote for their own reviews
+func (s *reviewService) VoteReview(ctx context.Context, id int, vote *models.ReviewVote) error {
+	userID, err := auth.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if r.UserID == userID {
+		return fmt.Errorf("%w: authors can't vote for their own reviews", models.ErrForbidden)
+	}
+
+	audit.SetEntity(ctx, auditReview, id)
+
+	return s.repo.Vote(ctx, id, userID, vote)
+}
+
+func (s *reviewService) DeleteReviewVote(ctx context.Context, id int) error {
+	userID, err := auth.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditReview, id)
+
+	return s.repo.DeleteVote(ctx, id, userID)
+}
+
+func (s *reviewService) listReviews(ctx context.Context, q *models.ReviewQuery) (*models.ReviewList, error) {
+	q.Limit, q.Offset = normalizePage(q.Limit, q.Offset)
+
+	reviews, total, err := s.repo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ReviewList{
+		Reviews: reviews,
+		Total:   total,
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+	}, nil
+}
+
+// authorOf returns the review if current user wrote it
+func (s *reviewService) authorOf(ctx context.Context, id int) (*models.Review, error) {
+	userID, err := auth.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.UserID != userID {
+		return nil, fmt.Errorf("%w: only author can change review %d", models.ErrForbidden, id)
+	}
+
+	return r, nil
+}
+
+func (s *reviewService) isAuthor(ctx context.Context, r *models.Review) bool {
+	userID, err := auth.CurrentUser(ctx)
+	return err == nil && r.UserID == userID
+}
+
+func validateReview(r *models.Review) error {
+	v := &ValidationError{}
+	validateText(v, "title", r.Title, MaxTitleLength, false)
+	validateText(v, "body", r.Body, MaxReviewLength, true)
+
+	return v.OrNil()
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/role"
+)
+
+// RoleService interface describes structs that are used for creating role handlers,
+// it also resolves roles of authenticated callers
+type RoleService interface {
+	auth.RoleResolver
+	ListRoles(context.Context) ([]*models.RoleAssignment, error)
+	SetRole(context.Context, *models.RoleAssignment) error
+	DeleteRole(ctx context.Context, subject string) error
+}
+
+const auditRole = "role"
+
+type roleService struct {
+	repo   role.Repository
+	admins map[string]bool
+}
+
+// NewRoleService creates new instance of RoleService interface,
+// admins are subjects which are admins whatever is stored, so roles can be granted on a fresh database
+func NewRoleService(r role.Repository, admins []string) RoleService {
+	s := &roleService{repo: r, admins: make(map[string]bool, len(admins))}
+	for _, subject := range admins {
+		s.admins[subject] = true
+	}
+
+	return s
+}
+
+// ResolveRole returns stored role of subject, subjects without one are viewers
+func (s *roleService) ResolveRole(ctx context.Context, subject string) (string, error) {
+	if s.admins[subject] {
+		return models.RoleAdmin, nil
+	}
+
+	a, err := s.repo.Get(ctx, subject)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.RoleViewer, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return a.Role, nil
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]*models.RoleAssignment, error) {
+	if err := auth.Authorize(ctx, auth.ActionManageUsers); err != nil {
+		return nil, err
+	}
+
+	return s.repo.List(ctx)
+}
+
+func (s *roleService) SetRole(ctx context.Context, a *models.RoleAssignment) error {
+	if err := auth.Authorize(ctx, auth.ActionManageUsers); err != nil {
+		return err
+	}
+
+	v := &ValidationError{}
+	if a.Subject == "" {
+		v.Add("subject", CodeRequired, "subject is required")
+	}
+	if !auth.ValidRole(a.Role) {
+		v.Add("role", CodeInvalid, "role must be viewer, editor or admin")
+	}
+	if err := v.OrNil(); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditRole, a.Subject)
+
+	if err := s.repo.Set(ctx, a); err != nil {
+		return err
+	}
+
+	audit.SetAfter(ctx, a)
+
+	return nil
+}
+
+func (s *roleService) DeleteRole(ctx context.Context, subject string) error {
+	if err := auth.Authorize(ctx, auth.ActionManageUsers); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditRole, subject)
+
+	return s.repo.Delete(ctx, subject)
+}
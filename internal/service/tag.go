@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/movie"
+	"github.com/CAATHARSIS/movies-library/internal/repository/tag"
+)
+
+// Sizes of tag cloud
+const (
+	DefaultTagCloudSize = 50
+	MaxTagCloudSize     = 200
+)
+
+// TagService interface describes structs that are used for creating tag handlers.
+// Tag names are case-insensitive, they are stored lowercase with single spaces between words
+type TagService interface {
+	// AttachTags adds tags to movie and returns all of its tags
+	AttachTags(ctx context.Context, movieID int, tags *models.MovieTags) ([]string, error)
+	DetachTag(ctx context.Context, movieID int, name string) error
+	ListMovieTags(ctx context.Context, movieID int) ([]string, error)
+	SuggestTags(ctx context.Context, text string, limit int) ([]*models.Tag, error)
+	// TagCloud returns the most used tags with their usage counts
+	TagCloud(ctx context.Context, limit int) ([]*models.Tag, error)
+}
+
+type tagService struct {
+	repo   tag.Repository
+	movies movie.Repository
+}
+
+// NewTagService creates new instance of TagService interface,
+// tags are changed through movies so each change bumps movie version
+func NewTagService(r tag.Repository, movies movie.Repository) TagService {
+	return &tagService{repo: r, movies: movies}
+}
+
+func (s *tagService) AttachTags(ctx context.Context, movieID int, t *models.MovieTags) ([]string, error) {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	names := normalizeTags(t.Tags)
+
+	v := &ValidationError{}
+
+	switch {
+	case len(names) == 0:
+		v.Add("tags", CodeRequired, "at least one tag is required")
+	case len(names) > MaxTags:
+		v.Add("tags", CodeTooMany, fmt.Sprintf("at most %d tags can be attached at once", MaxTags))
+	}
+
+	for _, name := range names {
+		if utf8.RuneCountInString(name) > MaxTagLength {
+			v.Add("tags", CodeTooLong, fmt.Sprintf("tag %q must be at most %d characters", name, MaxTagLength))
+		}
+	}
+
+	if err := v.OrNil(); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.updateTags(ctx, movieID, func(current *models.Movie) error {
+		for _, name := range names {
+			if !slices.Contains(current.Tags, name) {
+				current.Tags = append(current.Tags, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.Tags, nil
+}
+
+func (s *tagService) DetachTag(ctx context.Context, movieID int, name string) error {
+	if err := auth.Authorize(ctx, auth.ActionEdit); err != nil {
+		return err
+	}
+
+	name = normalizeTag(name)
+
+	_, err := s.updateTags(ctx, movieID, func(current *models.Movie) error {
+		i := slices.Index(current.Tags, name)
+		if i < 0 {
+			return fmt.Errorf("tag of movie %s: %w", name, models.ErrNotFound)
+		}
+
+		current.Tags = slices.Delete(current.Tags, i, i+1)
+		return nil
+	})
+
+	return err
+}
+
+// updateTags changes tags of the movie which isn't in trash and records the change in audit entry of ctx
+func (s *tagService) updateTags(ctx context.Context, movieID int, fn func(*models.Movie) error) (*models.Movie, error) {
+	audit.SetEntity(ctx, auditMovie, movieID)
+
+	updated, err := s.movies.Update(ctx, movieID, func(current *models.Movie) error {
+		audit.SetBefore(ctx, current)
+		return fn(current)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetAfter(ctx, updated)
+
+	return updated, nil
+}
+
+func (s *tagService) ListMovieTags(ctx context.Context, movieID int) ([]string, error) {
+	return s.repo.ListByMovie(ctx, movieID)
+}
+
+func (s *tagService) SuggestTags(ctx context.Context, text string, limit int) ([]*models.Tag, error) {
+	if limit <= 0 {
+		limit = DefaultSuggestions
+	}
+
+	if limit > MaxSuggestions {
+		limit = MaxSuggestions
+	}
+
+	return s.repo.Suggest(ctx, normalizeTag(text), limit)
+}
+
+func (s *tagService) TagCloud(ctx context.Context, limit int) ([]*models.Tag, error) {
+	if limit <= 0 {
+		limit = DefaultTagCloudSize
+	}
+
+	if limit > MaxTagCloudSize {
+		limit = MaxTagCloudSize
+	}
+
+	return s.repo.MostUsed(ctx, limit)
+}
+
+// normalizeTag lowercases name and collapses whitespace, so "Time  Travel" and "time travel" are the same tag
+func normalizeTag(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// normalizeTags normalizes names dropping empty and repeated ones
+func normalizeTags(names []string) []string {
+	if names == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = normalizeTag(name)
+		if name != "" && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+
+	return normalized
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	if normalizeTags(nil) != nil {
+		t.Error("Expected nil tags to stay nil")
+	}
+
+	got := normalizeTags([]string{" Time  Travel ", "heist", "time travel", "  ", "HEIST"})
+	if !slices.Equal(got, []string{"time travel", "heist"}) {
+		t.Errorf("Unexpected normalized tags %v", got)
+	}
+}
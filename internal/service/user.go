@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/user"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Lifetimes of tokens issued on login
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Password length limits, bcrypt ignores bytes after the 72nd
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+const auditUser = "user"
+
+// errInvalidCredentials is the same for unknown email and wrong password
+var errInvalidCredentials = fmt.Errorf("%w: invalid email or password", models.ErrUnauthorized)
+
+// UserService interface describes structs that are used for creating user handlers
+type UserService interface {
+	Register(context.Context, *models.Registration) (*models.User, error)
+	Login(context.Context, *models.Credentials) (*models.TokenPair, error)
+	// Refresh rotates refresh token, reuse of rotated token revokes every token of its login
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error)
+	// Logout revokes refresh token and every token rotated from the same login
+	Logout(ctx context.Context, refreshToken string) error
+	// ChangePassword changes password of the current user and revokes all their refresh tokens
+	ChangePassword(context.Context, *models.PasswordChange) error
+	CurrentUser(context.Context) (*models.User, error)
+}
+
+type userService struct {
+	repo   user.Repository
+	issuer *auth.Issuer
+}
+
+// NewUserService creates new instance of UserService interface, issuer signs access tokens
+func NewUserService(r user.Repository, issuer *auth.Issuer) UserService {
+	return &userService{repo: r, issuer: issuer}
+}
+
+func (s *userService) Register(ctx context.Context, reg *models.Registration) (*models.User, error) {
+	reg.Email = strings.TrimSpace(reg.Email)
+	reg.Name = strings.TrimSpace(reg.Name)
+
+	v := &ValidationError{}
+	if reg.Email == "" {
+		v.Add("email", CodeRequired, "email is required")
+	} else if addr, err := mail.ParseAddress(reg.Email); err != nil || addr.Address != reg.Email {
+		v.Add("email", CodeInvalid, "email is invalid")
+	}
+	validatePassword(v, "password", reg.Password)
+	if err := v.OrNil(); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(reg.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	u := &models.User{Email: reg.Email, Name: reg.Name, PasswordHash: string(hash)}
+	if err := s.repo.Create(ctx, u); err != nil {
+		if errors.Is(err, models.ErrConflict) {
+			return nil, fmt.Errorf("%w: email %s is taken", models.ErrConflict, reg.Email)
+		}
+		return nil, err
+	}
+
+	audit.SetEntity(ctx, auditUser, u.ID)
+	audit.SetAfter(ctx, u)
+
+	return u, nil
+}
+
+func (s *userService) Login(ctx context.Context, c *models.Credentials) (*models.TokenPair, error) {
+	u, err := s.repo.GetByEmail(ctx, strings.TrimSpace(c.Email))
+	if errors.Is(err, models.ErrNotFound) {
+		// unknown emails take as long as wrong passwords, so they can't be told apart
+		bcrypt.CompareHashAndPassword(dummyHash(), []byte(c.Password))
+		return nil, errInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(c.Password)) != nil {
+		return nil, errInvalidCredentials
+	}
+
+	audit.SetActor(ctx, auth.UserSubject(u.ID))
+	audit.SetEntity(ctx, auditUser, u.ID)
+
+	// access token is issued first, so failing issuer leaves no refresh token behind
+	access, err := s.issuer.IssueUserToken(u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := newRefreshToken(u.ID, auth.NewSecret())
+	if err := s.repo.CreateRefreshToken(ctx, refresh.RefreshToken); err != nil {
+		return nil, err
+	}
+
+	return s.tokenPair(access, refresh.secret), nil
+}
+
+func (s *userService) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	current, err := s.activeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := s.issuer.IssueUserToken(current.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := newRefreshToken(current.UserID, current.FamilyID)
+
+	err = s.repo.RotateRefreshToken(ctx, current.ID, next.RefreshToken)
+	if errors.Is(err, models.ErrNotFound) {
+		// token was rotated by a concurrent request
+		return nil, s.revokeReused(ctx, current)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	audit.SetActor(ctx, auth.UserSubject(current.UserID))
+
+	return s.tokenPair(access, next.secret), nil
+}
+
+func (s *userService) Logout(ctx context.Context, refreshToken string) error {
+	current, err := s.activeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	audit.SetActor(ctx, auth.UserSubject(current.UserID))
+
+	return s.repo.RevokeRefreshFamily(ctx, current.FamilyID)
+}
+
+func (s *userService) ChangePassword(ctx context.Context, change *models.PasswordChange) error {
+	u, err := s.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(change.CurrentPassword)) != nil {
+		v := &ValidationError{}
+		v.Add("current_password", CodeInvalid, "current password is wrong")
+		return v
+	}
+
+	v := &ValidationError{}
+	validatePassword(v, "new_password", change.NewPassword)
+	if err := v.OrNil(); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(change.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	audit.SetEntity(ctx, auditUser, u.ID)
+
+	if err := s.repo.UpdatePassword(ctx, u.ID, string(hash)); err != nil {
+		return err
+	}
+
+	return s.repo.RevokeUserRefreshTokens(ctx, u.ID)
+}
+
+// CurrentUser returns user of access token in ctx
+func (s *userService) CurrentUser(ctx context.Context) (*models.User, error) {
+	userID, err := auth.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, userID)
+}
+
+// activeRefreshToken returns stored state of refresh token which can be used,
+// reuse of revoked token revokes its whole family
+func (s *userService) activeRefreshToken(ctx context.Context, refreshToken string) (*models.RefreshToken, error) {
+	current, err := s.repo.GetRefreshToken(ctx, auth.HashSecret(refreshToken))
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("%w: refresh token is invalid", models.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		return nil, s.revokeReused(ctx, current)
+	}
+
+	if !current.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: refresh token is expired", models.ErrUnauthorized)
+	}
+
+	return current, nil
+}
+
+// revokeReused revokes family of token which was used after rotation, it may be stolen
+func (s *userService) revokeReused(ctx context.Context, token *models.RefreshToken) error {
+	if err := s.repo.RevokeRefreshFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: refresh token was already used, login again", models.ErrUnauthorized)
+}
+
+func (s *userService) tokenPair(access, refreshToken string) *models.TokenPair {
+	return &models.TokenPair{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.issuer.TTL().Seconds()),
+		RefreshToken: refreshToken,
+	}
+}
+
+// issuedRefreshToken keeps secret of refresh token next to its stored state
+type issuedRefreshToken struct {
+	*models.RefreshToken
+	secret string
+}
+
+func newRefreshToken(userID int, familyID string) *issuedRefreshToken {
+	secret := auth.NewSecret()
+
+	return &issuedRefreshToken{
+		RefreshToken: &models.RefreshToken{
+			UserID:    userID,
+			FamilyID:  familyID,
+			Hash:      auth.HashSecret(secret),
+			ExpiresAt: time.Now().Add(RefreshTokenTTL),
+		},
+		secret: secret,
+	}
+}
+
+func validatePassword(v *ValidationError, field, password string) {
+	switch {
+	case password == "":
+		v.Add(field, CodeRequired, "password is required")
+	case utf8.RuneCountInString(password) < MinPasswordLength:
+		v.Add(field, CodeInvalid, fmt.Sprintf("password must be at least %d characters", MinPasswordLength))
+	case len(password) > MaxPasswordLength:
+		v.Add(field, CodeInvalid, fmt.Sprintf("password must be at most %d bytes", MaxPasswordLength))
+	}
+}
+
+var (
+	dummyHashOnce  sync.Once
+	dummyHashValue []byte
+)
+
+// dummyHash is compared with passwords of unknown users
+func dummyHash() []byte {
+	dummyHashOnce.Do(func() {
+		dummyHashValue, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	})
+
+	return dummyHashValue
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/pkg/iso"
+)
+
+// Codes of field validation errors, they are stable and meant for clients
+const (
+	CodeRequired     = "required"
+	CodeInvalid      = "invalid"
+	CodeTooLong      = "too_long"
+	CodeOutOfRange   = "out_of_range"
+	CodeTooMany      = "too_many"
+	CodeUnknownGenre = "unknown_genre"
+	CodeUnknownField = "unknown_field"
+)
+
+// Limits of text fields
+const (
+	MaxNameLength        = 255
+	MaxTitleLength       = 255
+	MaxDirectorLength    = 255
+	MaxGenreLength       = 100
+	MaxTagLength         = 50
+	MaxDescriptionLength = 5000
+	MaxReviewLength      = 10000
+	MaxURLLength         = 2048
+	MaxGenres            = 10
+	MaxFranchiseMovies   = 500
+	MaxTags              = 20
+	MaxCountries         = 20
+	MaxLanguages         = 20
+	MaxCertifications    = 50
+	MaxRatingLength      = 10
+	// MaxRuntime is 30 days in minutes, experimental films run for weeks
+	MaxRuntime = 43200
+)
+
+// ratingPattern matches age ratings like "PG-13", "12A" or "TV-MA"
+var ratingPattern = regexp.MustCompile(`^[0-9A-Za-z]+([ +-][0-9A-Za-z]*)*$`)
+
+// earliestReleaseDate is the year of the first known motion picture
+var earliestReleaseDate = time.Date(1888, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// FieldError describes single invalid field of request
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of request
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		fields = append(fields, f.Field+": "+f.Message)
+	}
+
+	return "validation failed: " + strings.Join(fields, "; ")
+}
+
+// Unwrap makes ValidationError match models.ErrValidation
+func (e *ValidationError) Unwrap() error {
+	return models.ErrValidation
+}
+
+// Add appends invalid field to the error
+func (e *ValidationError) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// OrNil returns nil when there are no invalid fields, so result can be returned as error
+func (e *ValidationError) OrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// validateMovieFields adds errors of invalid movie fields to v
+func validateMovieFields(v *ValidationError, movie *models.Movie) {
+	validateText(v, "title", movie.Title, MaxTitleLength, true)
+	validateText(v, "director", movie.Director, MaxDirectorLength, true)
+	validateText(v, "genre", movie.Genre, MaxGenreLength, false)
+	validateText(v, "description", movie.Description, MaxDescriptionLength, false)
+
+	latestReleaseDate := time.Now().AddDate(10, 0, 0)
+
+	switch {
+	case movie.ReleaseDate.IsZero():
+		v.Add("release_date", CodeRequired, "release date is required")
+	case movie.ReleaseDate.Before(earliestReleaseDate) || movie.ReleaseDate.After(latestReleaseDate):
+		v.Add("release_date", CodeOutOfRange, fmt.Sprintf(
+			"release date must be between %s and %s",
+			earliestReleaseDate.Format(time.DateOnly),
+			latestReleaseDate.Format(time.DateOnly),
+		))
+	}
+
+	if len(movie.Genres) > MaxGenres {
+		v.Add("genres", CodeTooMany, fmt.Sprintf("at most %d genres are allowed", MaxGenres))
+	}
+
+	validateText(v, "original_title", movie.OriginalTitle, MaxTitleLength, false)
+
+	if movie.Runtime != nil && (*movie.Runtime < 1 || *movie.Runtime > MaxRuntime) {
+		v.Add("runtime", CodeOutOfRange, fmt.Sprintf("runtime must be between 1 and %d minutes", MaxRuntime))
+	}
+
+	validateCodes(v, "countries", movie.Countries, MaxCountries, iso.IsCountry, "ISO 3166-1 country")
+	validateCodes(v, "languages", movie.Languages, MaxLanguages, iso.IsLanguage, "ISO 639-1 language")
+
+	if len(movie.Certifications) > MaxCertifications {
+		v.Add("certifications", CodeTooMany, fmt.Sprintf("at most %d certifications are allowed", MaxCertifications))
+	}
+
+	for _, country := range slices.Sorted(maps.Keys(movie.Certifications)) {
+		rating := movie.Certifications[country]
+
+		switch {
+		case !iso.IsCountry(country):
+			v.Add("certifications", CodeInvalid, fmt.Sprintf("%q is not ISO 3166-1 country code", country))
+		case rating == "":
+			v.Add("certifications", CodeRequired, fmt.Sprintf("rating in %s is required", country))
+		case utf8.RuneCountInString(rating) > MaxRatingLength:
+			v.Add("certifications", CodeTooLong, fmt.Sprintf("rating in %s must be at most %d characters", country, MaxRatingLength))
+		case !ratingPattern.MatchString(rating):
+			v.Add("certifications", CodeInvalid, fmt.Sprintf("rating %q in %s is invalid", rating, country))
+		}
+	}
+
+	validateMoney(v, "budget", movie.Budget)
+	validateMoney(v, "box_office", movie.BoxOffice)
+}
+
+// validateCodes checks that codes are valid and there are at most limit of them
+func validateCodes(v *ValidationError, field string, codes []string, limit int, valid func(string) bool, kind string) {
+	if len(codes) > limit {
+		v.Add(field, CodeTooMany, fmt.Sprintf("at most %d %s are allowed", limit, field))
+	}
+
+	for _, code := range codes {
+		if !valid(code) {
+			v.Add(field, CodeInvalid, fmt.Sprintf("%q is not %s code", code, kind))
+		}
+	}
+}
+
+func validateMoney(v *ValidationError, field string, money *models.Money) {
+	if money == nil {
+		return
+	}
+
+	if money.Amount < 0 {
+		v.Add(field, CodeOutOfRange, field+" amount must not be negative")
+	}
+
+	if money.Currency == "" {
+		v.Add(field, CodeRequired, field+" currency is required")
+	} else if !iso.IsCurrency(money.Currency) {
+		v.Add(field, CodeInvalid, fmt.Sprintf("%q is not ISO 4217 currency code", money.Currency))
+	}
+}
+
+// normalizeMovieCodes brings ISO codes of movie to the case standards use and drops repeated ones,
+// so "us" and "US" are the same country
+func normalizeMovieCodes(movie *models.Movie) {
+	movie.Countries = normalizeCodes(movie.Countries, strings.ToUpper)
+	movie.Languages = normalizeCodes(movie.Languages, strings.ToLower)
+
+	if movie.Certifications != nil {
+		certifications := make(map[string]string, len(movie.Certifications))
+		for country, rating := range movie.Certifications {
+			certifications[strings.ToUpper(strings.TrimSpace(country))] = strings.TrimSpace(rating)
+		}
+		movie.Certifications = certifications
+	}
+
+	for _, money := range []*models.Money{movie.Budget, movie.BoxOffice} {
+		if money != nil {
+			money.Currency = strings.ToUpper(strings.TrimSpace(money.Currency))
+		}
+	}
+}
+
+func normalizeCodes(codes []string, toCase func(string) string) []string {
+	if codes == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = toCase(strings.TrimSpace(code))
+		if !slices.Contains(normalized, code) {
+			normalized = append(normalized, code)
+		}
+	}
+
+	return normalized
+}
+
+func validateText(v *ValidationError, field, value string, maxLength int, required bool) {
+	if required && strings.TrimSpace(value) == "" {
+		v.Add(field, CodeRequired, field+" is required")
+		return
+	}
+
+	if utf8.RuneCountInString(value) > maxLength {
+		v.Add(field, CodeTooLong, fmt.Sprintf("%s must be at most %d characters", field, maxLength))
+	}
+}
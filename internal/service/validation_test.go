@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/models"
+)
+
+// checkMovieFields returns validation error of movie fields, genres are not looked up
+func checkMovieFields(movie *models.Movie) error {
+	v := &ValidationError{}
+	validateMovieFields(v, movie)
+
+	return v.OrNil()
+}
+
+func TestValidateMovie(t *testing.T) {
+	valid := models.Movie{
+		Title:       "Heat",
+		Director:    "Michael Mann",
+		ReleaseDate: time.Date(1995, time.December, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	if err := checkMovieFields(&valid); err != nil {
+		t.Errorf("Expected valid movie, got %v", err)
+	}
+
+	invalid := valid
+	invalid.Title = " "
+	invalid.Director = strings.Repeat("a", MaxDirectorLength+1)
+	invalid.ReleaseDate = time.Time{}
+	invalid.Genres = make([]string, MaxGenres+1)
+	invalid.Runtime = new(int)
+	invalid.Countries = []string{"US", "XX"}
+	invalid.Languages = []string{"english"}
+	invalid.Certifications = map[string]string{"US": "PG 13!"}
+	invalid.Budget = &models.Money{Amount: -1, Currency: "USD"}
+	invalid.BoxOffice = &models.Money{Amount: 1, Currency: "XYZ"}
+
+	var verr *ValidationError
+	if err := checkMovieFields(&invalid); !errors.As(err, &verr) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+
+	expected := map[string]string{
+		"title":          CodeRequired,
+		"director":       CodeTooLong,
+		"release_date":   CodeRequired,
+		"genres":         CodeTooMany,
+		"runtime":        CodeOutOfRange,
+		"countries":      CodeInvalid,
+		"languages":      CodeInvalid,
+		"certifications": CodeInvalid,
+		"budget":         CodeOutOfRange,
+		"box_office":     CodeInvalid,
+	}
+
+	if len(verr.Fields) != len(expected) {
+		t.Fatalf("Expected %d invalid fields, got %+v", len(expected), verr.Fields)
+	}
+
+	for _, field := range verr.Fields {
+		if expected[field.Field] != field.Code {
+			t.Errorf("Unexpected code %s for field %s", field.Code, field.Field)
+		}
+	}
+}
+
+func TestNormalizeMovieCodes(t *testing.T) {
+	movie := models.Movie{
+		Countries:      []string{"us", " GB", "US"},
+		Languages:      []string{"EN", "en"},
+		Certifications: map[string]string{"us ": " PG-13"},
+		Budget:         &models.Money{Amount: 60000000, Currency: "usd"},
+	}
+
+	normalizeMovieCodes(&movie)
+
+	if !slices.Equal(movie.Countries, []string{"US", "GB"}) {
+		t.Errorf("Unexpected countries %v", movie.Countries)
+	}
+	if !slices.Equal(movie.Languages, []string{"en"}) {
+		t.Errorf("Unexpected languages %v", movie.Languages)
+	}
+	if movie.Certifications["US"] != "PG-13" || len(movie.Certifications) != 1 {
+		t.Errorf("Unexpected certifications %v", movie.Certifications)
+	}
+	if movie.Budget.Currency != "USD" {
+		t.Errorf("Unexpected budget currency %q", movie.Budget.Currency)
+	}
+
+	if err := checkMovieFields(&models.Movie{
+		Title:          "Heat",
+		Director:       "Michael Mann",
+		ReleaseDate:    time.Date(1995, time.December, 15, 0, 0, 0, 0, time.UTC),
+		Countries:      movie.Countries,
+		Languages:      movie.Languages,
+		Certifications: movie.Certifications,
+		Budget:         movie.Budget,
+	}); err != nil {
+		t.Errorf("Expected normalized movie to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/CAATHARSIS/movies-library/internal/audit"
+	"github.com/CAATHARSIS/movies-library/internal/auth"
+	"github.com/CAATHARSIS/movies-library/internal/models"
+	"github.com/CAATHARSIS/movies-library/internal/repository/watchlist"
+)
+
+// WatchlistService interface describes structs that are used for creating watchlist handlers.
+// Users work with their own lists, admins can work with lists of anyone
+type WatchlistService interface {
+	AddToWatchlist(ctx context.Context, userID int, item *models.WatchlistItem) error
+	RemoveFromWatchlist(ctx context.Context, userID, movieID int) error
+	ListWatchlist(ctx context.Context, userID int) ([]*models.WatchlistItem, error)
+	ReorderWatchlist(ctx context.Context, userID int, order *models.ListOrder) error
+	AddToHistory(ctx context.Context, userID int, entry *models.HistoryEntry) error
+	RemoveFromHistory(ctx context.Context, userID, movieID int) error
+	ListHistory(ctx context.Context, userID int) ([]*models.HistoryEntry, error)
+	ReorderHistory(ctx context.Context, userID int, order *models.ListOrder) error
+}
+
+type watchlistService struct {
+	repo watchlist.Repository
+}
+
+// NewWatchlistService creates new instance of WatchlistService interface
+func NewWatchlistService(r watchlist.Repository) WatchlistService {
+	return &watchlistService{repo: r}
+}
+
+func (s *watchlistService) AddToWatchlist(ctx context.Context, userID int, item *models.WatchlistItem) error {
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditUser, userID)
+
+	return s.repo.AddToWatchlist(ctx, userID, item)
+}
+
+func (s *watchlistService) RemoveFromWatchlist(ctx context.Context, userID, movieID int) error {
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditUser, userID)
+
+	return s.repo.RemoveFromWatchlist(ctx, userID, movieID)
+}
+
+func (s *watchlistService) ListWatchlist(ctx context.Context, userID int) ([]*models.WatchlistItem, error) {
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListWatchlist(ctx, userID)
+}
+
+func (s *watchlistService) ReorderWatchlist(ctx context.Context, userID int, order *models.ListOrder) error {
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditUser, userID)
+
+	return s.repo.ReorderWatchlist(ctx, userID, order.MovieIDs, func(current []int) error {
+		return validateOrder(order, current)
+	})
+}
+
+// AddToHistory marks movie as watched, marking it again replaces watched date and rewatch count
+func (s *watchlistService) AddToHistory(ctx context.Context, userID int, entry *models.HistoryEntry) error {
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return err
+	}
+
+	v := &ValidationError{}
+	if entry.WatchedAt != nil && entry.WatchedAt.After(time.Now()) {
+		v.Add("watched_at", CodeOutOfRange, "watched date can't be in the future")
+	}
+	if entry.RewatchCount < 0 {
+		v.Add("rewatch_count", CodeOutOfRange, "rewatch count can't be negative")
+	}
+	if err := v.OrNil(); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditUser, userID)
+
+	return s.repo.AddToHistory(ctx, userID, entry)
+}
+
+func (s *watchlistService) RemoveFromHistory(ctx context.Context, userID, movieID int) error {
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditUser, userID)
+
+	return s.repo.RemoveFromHistory(ctx, userID, movieID)
+}
+
+func (s *watchlistService) ListHistory(ctx context.Context, userID int) ([]*models.HistoryEntry, error) {
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListHistory(ctx, userID)
+}
+
+func (s *watchlistService) ReorderHistory(ctx context.Context, userID int, order *models.ListOrder) error {
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return err
+	}
+
+	audit.SetEntity(ctx, auditUser, userID)
+
+	return s.repo.ReorderHistory(ctx, userID, order.MovieIDs, func(current []int) error {
+		return validateOrder(order, current)
+	})
+}
+
+// authorizeOwner lets through user with userID and admins
+func authorizeOwner(ctx context.Context, userID int) error {
+	if current, err := auth.CurrentUser(ctx); err == nil && current == userID {
+		return nil
+	}
+
+	return auth.Authorize(ctx, auth.ActionManageUsers)
+}
+
+// validateOrder checks that order is a permutation of current movies of the list
+func validateOrder(order *models.ListOrder, current []int) error {
+	ids := slices.Clone(order.MovieIDs)
+	slices.Sort(ids)
+	slices.Sort(current)
+
+	if !slices.Equal(ids, current) {
+		v := &ValidationError{}
+		v.Add("movie_ids", CodeInvalid, fmt.Sprintf("order must name each of %d movies of the list exactly once", len(current)))
+		return v
+	}
+
+	return nil
+}
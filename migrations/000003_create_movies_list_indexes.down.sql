@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS IDX_MOVIES_LOWER_GENRE;
+DROP INDEX IF EXISTS IDX_MOVIES_UPDATED_AT_ID;
+DROP INDEX IF EXISTS IDX_MOVIES_CREATED_AT_ID;
+DROP INDEX IF EXISTS IDX_MOVIES_RELEASE_DATE_ID;
+DROP INDEX IF EXISTS IDX_MOVIES_TITLE_ID;
\ No newline at end of file
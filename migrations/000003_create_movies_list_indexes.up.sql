@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_TITLE_ID ON MOVIES (TITLE, ID);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_RELEASE_DATE_ID ON MOVIES (RELEASE_DATE, ID);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_CREATED_AT_ID ON MOVIES (CREATED_AT, ID);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_UPDATED_AT_ID ON MOVIES (UPDATED_AT, ID);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_LOWER_GENRE ON MOVIES (LOWER(GENRE));
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS IDX_MOVIES_SEARCH_VECTOR;
+ALTER TABLE MOVIES DROP COLUMN IF EXISTS SEARCH_VECTOR;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE MOVIES
+    ADD COLUMN IF NOT EXISTS SEARCH_VECTOR TSVECTOR GENERATED ALWAYS AS (
+        SETWEIGHT(TO_TSVECTOR('english', COALESCE(TITLE, '')), 'A') ||
+        SETWEIGHT(TO_TSVECTOR('english', COALESCE(DIRECTOR, '')), 'B') ||
+        SETWEIGHT(TO_TSVECTOR('english', COALESCE(DESCRIPTION, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_SEARCH_VECTOR ON MOVIES USING GIN (SEARCH_VECTOR);
\ No newline at end of file
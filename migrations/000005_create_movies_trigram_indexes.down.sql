@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS IDX_MOVIES_DIRECTOR_TRGM;
+DROP INDEX IF EXISTS IDX_MOVIES_TITLE_TRGM;
+DROP EXTENSION IF EXISTS PG_TRGM;
\ No newline at end of file
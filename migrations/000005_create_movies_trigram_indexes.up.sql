@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS PG_TRGM;
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_TITLE_TRGM ON MOVIES USING GIN (TITLE GIN_TRGM_OPS);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_DIRECTOR_TRGM ON MOVIES USING GIN (DIRECTOR GIN_TRGM_OPS);
\ No newline at end of file
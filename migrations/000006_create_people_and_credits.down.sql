@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS MOVIE_CREDITS;
+DROP TABLE IF EXISTS PEOPLE;
\ No newline at end of file
@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS PEOPLE (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    NAME TEXT NOT NULL,
+    BIRTH_DATE DATE,
+    BIO TEXT,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UPDATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_PEOPLE_LOWER_NAME ON PEOPLE (LOWER(NAME));
+
+CREATE TRIGGER TRIGGER_UPDATE_PEOPLE_UPDATED_AT
+BEFORE UPDATE ON PEOPLE
+FOR EACH ROW
+EXECUTE FUNCTION UDPATE_UPDATED_AT();
+
+CREATE TABLE IF NOT EXISTS MOVIE_CREDITS (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    PERSON_ID INT NOT NULL REFERENCES PEOPLE (ID) ON DELETE CASCADE,
+    ROLE TEXT NOT NULL CHECK (ROLE IN ('director', 'writer', 'actor', 'composer')),
+    CHARACTER_NAME TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS IDX_MOVIE_CREDITS_UNIQUE ON MOVIE_CREDITS (MOVIE_ID, PERSON_ID, ROLE, CHARACTER_NAME);
+CREATE INDEX IF NOT EXISTS IDX_MOVIE_CREDITS_PERSON_ID ON MOVIE_CREDITS (PERSON_ID);
+
+-- co-directors are usually written as "A, B" or "A & B"
+CREATE OR REPLACE VIEW DIRECTOR_NAMES AS
+SELECT
+    ID AS MOVIE_ID,
+    BTRIM(NAME) AS NAME
+FROM
+    MOVIES,
+    REGEXP_SPLIT_TO_TABLE(DIRECTOR, '\s*(,|&)\s*') AS NAME
+WHERE
+    BTRIM(NAME) <> '';
+
+-- names are deduplicated case-insensitively only, so spellings like "Nolan", "C. Nolan" and
+-- "Christopher Nolan" still become different people, their credits have to be relinked by hand. Credits of
+-- new movies are matched to people the same way when movies are written
+INSERT INTO PEOPLE (NAME)
+SELECT DISTINCT ON (LOWER(NAME))
+    NAME
+FROM
+    DIRECTOR_NAMES
+ORDER BY
+    LOWER(NAME),
+    NAME;
+
+INSERT INTO MOVIE_CREDITS (MOVIE_ID, PERSON_ID, ROLE)
+SELECT DISTINCT
+    D.MOVIE_ID,
+    P.ID,
+    'director'
+FROM
+    DIRECTOR_NAMES D
+    JOIN PEOPLE P ON LOWER(P.NAME) = LOWER(D.NAME);
+
+DROP VIEW DIRECTOR_NAMES;
\ No newline at end of file
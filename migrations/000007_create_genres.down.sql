@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS MOVIE_GENRES;
+DROP TABLE IF EXISTS GENRES;
+ALTER TABLE MOVIES ALTER COLUMN GENRE DROP DEFAULT;
\ No newline at end of file
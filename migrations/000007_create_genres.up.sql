@@ -0,0 +1,124 @@
+CREATE TABLE IF NOT EXISTS GENRES (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    SLUG TEXT NOT NULL UNIQUE CHECK (SLUG ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    NAME TEXT NOT NULL,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS MOVIE_GENRES (
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    GENRE_ID INT NOT NULL REFERENCES GENRES (ID) ON DELETE RESTRICT,
+    PRIMARY KEY (MOVIE_ID, GENRE_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIE_GENRES_GENRE_ID ON MOVIE_GENRES (GENRE_ID);
+
+INSERT INTO GENRES (SLUG, NAME)
+VALUES
+    ('action', 'Action'),
+    ('adventure', 'Adventure'),
+    ('animation', 'Animation'),
+    ('biography', 'Biography'),
+    ('comedy', 'Comedy'),
+    ('crime', 'Crime'),
+    ('documentary', 'Documentary'),
+    ('drama', 'Drama'),
+    ('family', 'Family'),
+    ('fantasy', 'Fantasy'),
+    ('history', 'History'),
+    ('horror', 'Horror'),
+    ('musical', 'Musical'),
+    ('mystery', 'Mystery'),
+    ('romance', 'Romance'),
+    ('sci-fi', 'Sci-Fi'),
+    ('thriller', 'Thriller'),
+    ('war', 'War'),
+    ('western', 'Western')
+ON CONFLICT (SLUG) DO NOTHING;
+
+-- legacy genres are lists like "Drama, Crime" or "Sci-Fi/Action",
+-- each part is turned into a slug and common spellings are folded into one or more genres.
+-- Parts with non-ASCII letters like "Комедия" can't be slugged, they get slug from hash of their text,
+-- unmatched parts become genres named by their legacy text, so nothing is dropped
+CREATE OR REPLACE VIEW LEGACY_GENRE_SLUGS AS
+WITH PARTS AS (
+    SELECT
+        ID AS MOVIE_ID,
+        BTRIM(PART) AS TEXT
+    FROM
+        MOVIES,
+        REGEXP_SPLIT_TO_TABLE(GENRE, '\s*(,|/|;|\||&)\s*') AS PART
+),
+SLUGS AS (
+    SELECT
+        MOVIE_ID,
+        TEXT,
+        CASE
+            WHEN OCTET_LENGTH(TEXT) <> CHAR_LENGTH(TEXT) THEN 'legacy-' || LEFT(MD5(LOWER(TEXT)), 8)
+            ELSE BTRIM(REGEXP_REPLACE(LOWER(TEXT), '[^a-z0-9]+', '-', 'g'), '-')
+        END AS SLUG
+    FROM
+        PARTS
+),
+SYNONYMS (SLUG, CANONICAL) AS (
+    VALUES
+        ('scifi', 'sci-fi'),
+        ('sf', 'sci-fi'),
+        ('science-fiction', 'sci-fi'),
+        ('sci-fi-fantasy', 'sci-fi'),
+        ('sci-fi-fantasy', 'fantasy'),
+        ('action-adventure', 'action'),
+        ('action-adventure', 'adventure'),
+        ('romantic-comedy', 'romance'),
+        ('romantic-comedy', 'comedy'),
+        ('romcom', 'romance'),
+        ('romcom', 'comedy'),
+        ('crime-drama', 'crime'),
+        ('crime-drama', 'drama'),
+        ('animated', 'animation'),
+        ('cartoon', 'animation'),
+        ('biopic', 'biography'),
+        ('documentaries', 'documentary'),
+        ('docu', 'documentary'),
+        ('romantic', 'romance'),
+        ('musicals', 'musical'),
+        ('music', 'musical'),
+        ('historical', 'history'),
+        ('thrillers', 'thriller'),
+        ('comedies', 'comedy'),
+        ('dramas', 'drama')
+)
+SELECT DISTINCT
+    P.MOVIE_ID,
+    COALESCE(S.CANONICAL, P.SLUG) AS SLUG,
+    P.TEXT AS NAME
+FROM
+    SLUGS P
+    LEFT JOIN SYNONYMS S ON S.SLUG = P.SLUG
+WHERE
+    P.SLUG <> '';
+
+-- canonical genres already exist, so only unmatched parts are inserted here
+INSERT INTO GENRES (SLUG, NAME)
+SELECT
+    SLUG,
+    MIN(NAME)
+FROM
+    LEGACY_GENRE_SLUGS
+GROUP BY
+    SLUG
+ON CONFLICT (SLUG) DO NOTHING;
+
+INSERT INTO MOVIE_GENRES (MOVIE_ID, GENRE_ID)
+SELECT
+    L.MOVIE_ID,
+    G.ID
+FROM
+    LEGACY_GENRE_SLUGS L
+    JOIN GENRES G ON G.SLUG = L.SLUG
+ON CONFLICT DO NOTHING;
+
+DROP VIEW LEGACY_GENRE_SLUGS;
+
+-- genres are kept in MOVIE_GENRES now, old column stays only for compatibility
+ALTER TABLE MOVIES ALTER COLUMN GENRE SET DEFAULT '';
\ No newline at end of file
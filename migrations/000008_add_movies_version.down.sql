@@ -0,0 +1 @@
+ALTER TABLE MOVIES DROP COLUMN IF EXISTS VERSION;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE MOVIES
+    ADD COLUMN IF NOT EXISTS VERSION INTEGER NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS IDX_MOVIES_DELETED_AT_ID;
+DELETE FROM MOVIES WHERE DELETED_AT IS NOT NULL;
+ALTER TABLE MOVIES DROP COLUMN IF EXISTS DELETED_AT;
\ No newline at end of file
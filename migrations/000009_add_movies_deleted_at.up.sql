@@ -0,0 +1,4 @@
+ALTER TABLE MOVIES
+    ADD COLUMN IF NOT EXISTS DELETED_AT TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_DELETED_AT_ID ON MOVIES (DELETED_AT, ID) WHERE DELETED_AT IS NOT NULL;
\ No newline at end of file
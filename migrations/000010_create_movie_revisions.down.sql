@@ -0,0 +1 @@
+DROP TABLE IF EXISTS MOVIE_REVISIONS;
\ No newline at end of file
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS MOVIE_REVISIONS (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    VERSION INT NOT NULL,
+    ACTION TEXT NOT NULL CHECK (ACTION IN ('create', 'update', 'delete', 'restore')),
+    -- state of the movie right after the change
+    SNAPSHOT JSONB NOT NULL,
+    CHANGED_BY TEXT,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIE_REVISIONS_MOVIE_ID_CREATED_AT ON MOVIE_REVISIONS (MOVIE_ID, CREATED_AT);
+
+-- history of existing movies starts from their current state, it's known since last update
+INSERT INTO MOVIE_REVISIONS (MOVIE_ID, VERSION, ACTION, SNAPSHOT, CREATED_AT)
+SELECT
+    M.ID,
+    M.VERSION,
+    'create',
+    JSONB_BUILD_OBJECT(
+        'id', M.ID,
+        'title', M.TITLE,
+        'director', M.DIRECTOR,
+        'release_date', M.RELEASE_DATE,
+        'genre', M.GENRE,
+        'genres', ARRAY(
+            SELECT
+                G.SLUG
+            FROM
+                MOVIE_GENRES MG
+                JOIN GENRES G ON G.ID = MG.GENRE_ID
+            WHERE
+                MG.MOVIE_ID = M.ID
+            ORDER BY
+                G.SLUG
+        ),
+        'description', COALESCE(M.DESCRIPTION, ''),
+        'created_at', M.CREATED_AT,
+        'updated_at', M.UPDATED_AT,
+        'version', M.VERSION,
+        'deleted_at', M.DELETED_AT
+    ),
+    M.UPDATED_AT
+FROM
+    MOVIES M;
\ No newline at end of file
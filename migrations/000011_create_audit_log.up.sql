@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS AUDIT_LOG (
+    ID BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    ACTOR TEXT NOT NULL,
+    METHOD TEXT NOT NULL,
+    PATH TEXT NOT NULL,
+    STATUS INT NOT NULL,
+    REQUEST_ID TEXT NOT NULL DEFAULT '',
+    SOURCE_IP TEXT NOT NULL DEFAULT '',
+    ENTITY_TYPE TEXT NOT NULL DEFAULT '',
+    ENTITY_ID TEXT NOT NULL DEFAULT '',
+    BEFORE JSONB,
+    AFTER JSONB,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_AUDIT_LOG_CREATED_AT_ID ON AUDIT_LOG (CREATED_AT, ID);
+CREATE INDEX IF NOT EXISTS IDX_AUDIT_LOG_ACTOR ON AUDIT_LOG (ACTOR);
+CREATE INDEX IF NOT EXISTS IDX_AUDIT_LOG_ENTITY ON AUDIT_LOG (ENTITY_TYPE, ENTITY_ID);
+
+-- audit trail is append-only, rows can't be changed or removed even by the application
+CREATE OR REPLACE FUNCTION REJECT_AUDIT_LOG_CHANGE()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log is append-only';
+END;
+$$ LANGUAGE PLPGSQL;
+
+CREATE TRIGGER TRIGGER_AUDIT_LOG_APPEND_ONLY
+BEFORE UPDATE OR DELETE ON AUDIT_LOG
+FOR EACH ROW
+EXECUTE FUNCTION REJECT_AUDIT_LOG_CHANGE();
+
+CREATE TRIGGER TRIGGER_AUDIT_LOG_NO_TRUNCATE
+BEFORE TRUNCATE ON AUDIT_LOG
+FOR EACH STATEMENT
+EXECUTE FUNCTION REJECT_AUDIT_LOG_CHANGE();
\ No newline at end of file
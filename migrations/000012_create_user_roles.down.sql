@@ -0,0 +1 @@
+DROP TABLE IF EXISTS USER_ROLES;
\ No newline at end of file
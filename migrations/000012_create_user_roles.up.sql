@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS USER_ROLES (
+    SUBJECT TEXT PRIMARY KEY,
+    ROLE TEXT NOT NULL CHECK (ROLE IN ('viewer', 'editor', 'admin')),
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UPDATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TRIGGER TRIGGER_USER_ROLES_UPDATED_AT
+BEFORE UPDATE ON USER_ROLES
+FOR EACH ROW
+EXECUTE FUNCTION UDPATE_UPDATED_AT();
\ No newline at end of file
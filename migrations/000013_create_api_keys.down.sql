@@ -0,0 +1 @@
+DROP TABLE IF EXISTS API_KEYS;
\ No newline at end of file
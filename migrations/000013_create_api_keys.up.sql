@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS API_KEYS (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    NAME TEXT NOT NULL,
+    PREFIX TEXT NOT NULL,
+    KEY_HASH TEXT NOT NULL UNIQUE,
+    SCOPES TEXT[] NOT NULL CHECK (CARDINALITY(SCOPES) > 0),
+    CREATED_BY TEXT NOT NULL,
+    EXPIRES_AT TIMESTAMP WITH TIME ZONE,
+    LAST_USED_AT TIMESTAMP WITH TIME ZONE,
+    REVOKED_AT TIMESTAMP WITH TIME ZONE,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
\ No newline at end of file
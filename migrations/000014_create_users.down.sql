@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS REFRESH_TOKENS;
+DROP TABLE IF EXISTS USERS;
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS USERS (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    EMAIL TEXT NOT NULL,
+    NAME TEXT NOT NULL DEFAULT '',
+    PASSWORD_HASH TEXT NOT NULL,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UPDATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS IDX_USERS_EMAIL ON USERS (LOWER(EMAIL));
+
+CREATE TRIGGER TRIGGER_USERS_UPDATED_AT
+BEFORE UPDATE ON USERS
+FOR EACH ROW
+EXECUTE FUNCTION UDPATE_UPDATED_AT();
+
+-- refresh tokens are rotated on every use, tokens issued from one login share a family
+-- so reuse of a rotated token revokes the whole chain
+CREATE TABLE IF NOT EXISTS REFRESH_TOKENS (
+    ID BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    USER_ID INT NOT NULL REFERENCES USERS (ID) ON DELETE CASCADE,
+    FAMILY_ID TEXT NOT NULL,
+    TOKEN_HASH TEXT NOT NULL UNIQUE,
+    EXPIRES_AT TIMESTAMP WITH TIME ZONE NOT NULL,
+    REVOKED_AT TIMESTAMP WITH TIME ZONE,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_REFRESH_TOKENS_USER_ID ON REFRESH_TOKENS (USER_ID);
+CREATE INDEX IF NOT EXISTS IDX_REFRESH_TOKENS_FAMILY_ID ON REFRESH_TOKENS (FAMILY_ID);
\ No newline at end of file
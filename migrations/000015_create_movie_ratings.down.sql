@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS MOVIE_RATINGS;
+DROP FUNCTION IF EXISTS UPDATE_MOVIE_RATING_STATS();
+DROP TABLE IF EXISTS MOVIE_RATING_STATS;
\ No newline at end of file
@@ -0,0 +1,66 @@
+CREATE TABLE IF NOT EXISTS MOVIE_RATINGS (
+    USER_ID INT NOT NULL REFERENCES USERS (ID) ON DELETE CASCADE,
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    SCORE SMALLINT NOT NULL CHECK (SCORE BETWEEN 1 AND 10),
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UPDATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (USER_ID, MOVIE_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIE_RATINGS_MOVIE_ID ON MOVIE_RATINGS (MOVIE_ID);
+
+CREATE TRIGGER TRIGGER_MOVIE_RATINGS_UPDATED_AT
+BEFORE UPDATE ON MOVIE_RATINGS
+FOR EACH ROW
+EXECUTE FUNCTION UDPATE_UPDATED_AT();
+
+-- aggregates live apart from MOVIES so rating a movie doesn't touch its UPDATED_AT and VERSION,
+-- HISTOGRAM[i] is number of ratings with score i
+CREATE TABLE IF NOT EXISTS MOVIE_RATING_STATS (
+    MOVIE_ID INT PRIMARY KEY REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    RATING_COUNT INT NOT NULL DEFAULT 0,
+    RATING_SUM INT NOT NULL DEFAULT 0,
+    HISTOGRAM INT[] NOT NULL DEFAULT ARRAY_FILL(0, ARRAY[10]),
+    AVERAGE NUMERIC(4, 2) GENERATED ALWAYS AS (
+        CASE WHEN RATING_COUNT > 0 THEN ROUND(RATING_SUM::NUMERIC / RATING_COUNT, 2) ELSE 0 END
+    ) STORED
+);
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIE_RATING_STATS_AVERAGE ON MOVIE_RATING_STATS (AVERAGE, MOVIE_ID);
+
+-- stats are maintained incrementally, old score is taken back and new one is added
+CREATE OR REPLACE FUNCTION UPDATE_MOVIE_RATING_STATS()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE MOVIE_RATING_STATS
+        SET
+            RATING_COUNT = RATING_COUNT - 1,
+            RATING_SUM = RATING_SUM - OLD.SCORE,
+            HISTOGRAM[OLD.SCORE] = HISTOGRAM[OLD.SCORE] - 1
+        WHERE
+            MOVIE_ID = OLD.MOVIE_ID;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO MOVIE_RATING_STATS (MOVIE_ID)
+        VALUES (NEW.MOVIE_ID)
+        ON CONFLICT (MOVIE_ID) DO NOTHING;
+
+        UPDATE MOVIE_RATING_STATS
+        SET
+            RATING_COUNT = RATING_COUNT + 1,
+            RATING_SUM = RATING_SUM + NEW.SCORE,
+            HISTOGRAM[NEW.SCORE] = HISTOGRAM[NEW.SCORE] + 1
+        WHERE
+            MOVIE_ID = NEW.MOVIE_ID;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE PLPGSQL;
+
+CREATE TRIGGER TRIGGER_MOVIE_RATINGS_STATS
+AFTER INSERT OR UPDATE OF SCORE OR DELETE ON MOVIE_RATINGS
+FOR EACH ROW
+EXECUTE FUNCTION UPDATE_MOVIE_RATING_STATS();
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS REVIEW_VOTES;
+DROP FUNCTION IF EXISTS UPDATE_REVIEW_VOTE_COUNTS();
+DROP TABLE IF EXISTS REVIEWS;
\ No newline at end of file
@@ -0,0 +1,65 @@
+CREATE TABLE IF NOT EXISTS REVIEWS (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    USER_ID INT NOT NULL REFERENCES USERS (ID) ON DELETE CASCADE,
+    TITLE TEXT NOT NULL DEFAULT '',
+    BODY TEXT NOT NULL,
+    STATUS TEXT NOT NULL DEFAULT 'pending' CHECK (STATUS IN ('pending', 'approved', 'rejected')),
+    MODERATED_BY TEXT,
+    MODERATION_NOTE TEXT NOT NULL DEFAULT '',
+    MODERATED_AT TIMESTAMP WITH TIME ZONE,
+    HELPFUL_COUNT INT NOT NULL DEFAULT 0,
+    UNHELPFUL_COUNT INT NOT NULL DEFAULT 0,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UPDATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (MOVIE_ID, USER_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_REVIEWS_MOVIE_CREATED_AT ON REVIEWS (MOVIE_ID, STATUS, CREATED_AT);
+CREATE INDEX IF NOT EXISTS IDX_REVIEWS_MOVIE_HELPFUL ON REVIEWS (MOVIE_ID, STATUS, HELPFUL_COUNT);
+CREATE INDEX IF NOT EXISTS IDX_REVIEWS_STATUS ON REVIEWS (STATUS, CREATED_AT);
+
+-- vote counters don't count as update of review
+CREATE TRIGGER TRIGGER_REVIEWS_UPDATED_AT
+BEFORE UPDATE OF TITLE, BODY, STATUS ON REVIEWS
+FOR EACH ROW
+EXECUTE FUNCTION UDPATE_UPDATED_AT();
+
+CREATE TABLE IF NOT EXISTS REVIEW_VOTES (
+    REVIEW_ID INT NOT NULL REFERENCES REVIEWS (ID) ON DELETE CASCADE,
+    USER_ID INT NOT NULL REFERENCES USERS (ID) ON DELETE CASCADE,
+    HELPFUL BOOLEAN NOT NULL,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (REVIEW_ID, USER_ID)
+);
+
+-- vote counters of reviews are maintained incrementally like rating stats of movies
+CREATE OR REPLACE FUNCTION UPDATE_REVIEW_VOTE_COUNTS()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE REVIEWS
+        SET
+            HELPFUL_COUNT = HELPFUL_COUNT - OLD.HELPFUL::INT,
+            UNHELPFUL_COUNT = UNHELPFUL_COUNT - (NOT OLD.HELPFUL)::INT
+        WHERE
+            ID = OLD.REVIEW_ID;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE REVIEWS
+        SET
+            HELPFUL_COUNT = HELPFUL_COUNT + NEW.HELPFUL::INT,
+            UNHELPFUL_COUNT = UNHELPFUL_COUNT + (NOT NEW.HELPFUL)::INT
+        WHERE
+            ID = NEW.REVIEW_ID;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE PLPGSQL;
+
+CREATE TRIGGER TRIGGER_REVIEW_VOTES_COUNTS
+AFTER INSERT OR UPDATE OF HELPFUL OR DELETE ON REVIEW_VOTES
+FOR EACH ROW
+EXECUTE FUNCTION UPDATE_REVIEW_VOTE_COUNTS();
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS WATCH_HISTORY;
+DROP TABLE IF EXISTS WATCHLIST_ITEMS;
\ No newline at end of file
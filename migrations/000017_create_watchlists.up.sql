@@ -0,0 +1,29 @@
+-- entries of trashed movies are hidden by queries and come back on restore, purge removes them by cascade
+CREATE TABLE IF NOT EXISTS WATCHLIST_ITEMS (
+    USER_ID INT NOT NULL REFERENCES USERS (ID) ON DELETE CASCADE,
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    POSITION INT NOT NULL,
+    ADDED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (USER_ID, MOVIE_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_WATCHLIST_ITEMS_MOVIE_ID ON WATCHLIST_ITEMS (MOVIE_ID);
+
+CREATE TABLE IF NOT EXISTS WATCH_HISTORY (
+    USER_ID INT NOT NULL REFERENCES USERS (ID) ON DELETE CASCADE,
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    POSITION INT NOT NULL,
+    WATCHED_AT DATE,
+    REWATCH_COUNT INT NOT NULL DEFAULT 0 CHECK (REWATCH_COUNT >= 0),
+    ADDED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UPDATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (USER_ID, MOVIE_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_WATCH_HISTORY_MOVIE_ID ON WATCH_HISTORY (MOVIE_ID);
+
+-- reordering doesn't count as update of entry
+CREATE TRIGGER TRIGGER_WATCH_HISTORY_UPDATED_AT
+BEFORE UPDATE OF WATCHED_AT, REWATCH_COUNT ON WATCH_HISTORY
+FOR EACH ROW
+EXECUTE FUNCTION UDPATE_UPDATED_AT();
\ No newline at end of file
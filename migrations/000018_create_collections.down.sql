@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS COLLECTION_MOVIES;
+DROP TABLE IF EXISTS COLLECTIONS;
\ No newline at end of file
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS COLLECTIONS (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    SLUG TEXT NOT NULL UNIQUE CHECK (SLUG ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    NAME TEXT NOT NULL,
+    DESCRIPTION TEXT NOT NULL DEFAULT '',
+    COVER_URL TEXT NOT NULL DEFAULT '',
+    IS_PUBLIC BOOLEAN NOT NULL DEFAULT FALSE,
+    CREATED_BY TEXT NOT NULL,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UPDATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TRIGGER TRIGGER_COLLECTIONS_UPDATED_AT
+BEFORE UPDATE ON COLLECTIONS
+FOR EACH ROW
+EXECUTE FUNCTION UDPATE_UPDATED_AT();
+
+-- positions leave gaps between neighbours, so moving a movie updates only its own row
+-- until the gap is exhausted and the collection gets renumbered
+CREATE TABLE IF NOT EXISTS COLLECTION_MOVIES (
+    COLLECTION_ID INT NOT NULL REFERENCES COLLECTIONS (ID) ON DELETE CASCADE,
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    POSITION BIGINT NOT NULL,
+    ADDED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (COLLECTION_ID, MOVIE_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_COLLECTION_MOVIES_POSITION ON COLLECTION_MOVIES (COLLECTION_ID, POSITION);
+CREATE INDEX IF NOT EXISTS IDX_COLLECTION_MOVIES_MOVIE_ID ON COLLECTION_MOVIES (MOVIE_ID);
\ No newline at end of file
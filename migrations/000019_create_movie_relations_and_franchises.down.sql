@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS FRANCHISE_MOVIES;
+DROP TABLE IF EXISTS FRANCHISES;
+DROP TABLE IF EXISTS MOVIE_RELATIONS;
\ No newline at end of file
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS MOVIE_RELATIONS (
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    RELATED_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    TYPE TEXT NOT NULL CHECK (TYPE IN ('sequel', 'prequel', 'remake', 'spin_off')),
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (MOVIE_ID, RELATED_ID),
+    CHECK (MOVIE_ID <> RELATED_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIE_RELATIONS_RELATED_ID ON MOVIE_RELATIONS (RELATED_ID);
+
+CREATE TABLE IF NOT EXISTS FRANCHISES (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    SLUG TEXT NOT NULL UNIQUE CHECK (SLUG ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    NAME TEXT NOT NULL,
+    DESCRIPTION TEXT NOT NULL DEFAULT '',
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UPDATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TRIGGER TRIGGER_FRANCHISES_UPDATED_AT
+BEFORE UPDATE ON FRANCHISES
+FOR EACH ROW
+EXECUTE FUNCTION UDPATE_UPDATED_AT();
+
+CREATE TABLE IF NOT EXISTS FRANCHISE_MOVIES (
+    FRANCHISE_ID INT NOT NULL REFERENCES FRANCHISES (ID) ON DELETE CASCADE,
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    POSITION INT NOT NULL,
+    PRIMARY KEY (FRANCHISE_ID, MOVIE_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_FRANCHISE_MOVIES_MOVIE_ID ON FRANCHISE_MOVIES (MOVIE_ID);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS MOVIE_TAGS;
+DROP TABLE IF EXISTS TAGS;
\ No newline at end of file
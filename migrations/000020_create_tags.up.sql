@@ -0,0 +1,17 @@
+-- tag names are normalized by application: lowercase, trimmed, single spaces between words
+CREATE TABLE IF NOT EXISTS TAGS (
+    ID INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    NAME TEXT NOT NULL UNIQUE CHECK (NAME <> '' AND NAME = LOWER(BTRIM(NAME))),
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS IDX_TAGS_NAME_TRGM ON TAGS USING GIN (NAME GIN_TRGM_OPS);
+
+CREATE TABLE IF NOT EXISTS MOVIE_TAGS (
+    MOVIE_ID INT NOT NULL REFERENCES MOVIES (ID) ON DELETE CASCADE,
+    TAG_ID INT NOT NULL REFERENCES TAGS (ID) ON DELETE CASCADE,
+    CREATED_AT TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (MOVIE_ID, TAG_ID)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIE_TAGS_TAG_ID ON MOVIE_TAGS (TAG_ID);
\ No newline at end of file
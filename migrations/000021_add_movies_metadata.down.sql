@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS IDX_MOVIES_ORIGINAL_TITLE_TRGM;
+DROP INDEX IF EXISTS IDX_MOVIES_CERTIFICATIONS;
+DROP INDEX IF EXISTS IDX_MOVIES_LANGUAGES;
+DROP INDEX IF EXISTS IDX_MOVIES_COUNTRIES;
+DROP INDEX IF EXISTS IDX_MOVIES_RUNTIME;
+ALTER TABLE MOVIES
+    DROP COLUMN IF EXISTS BOX_OFFICE_CURRENCY,
+    DROP COLUMN IF EXISTS BOX_OFFICE,
+    DROP COLUMN IF EXISTS BUDGET_CURRENCY,
+    DROP COLUMN IF EXISTS BUDGET,
+    DROP COLUMN IF EXISTS CERTIFICATIONS,
+    DROP COLUMN IF EXISTS LANGUAGES,
+    DROP COLUMN IF EXISTS COUNTRIES,
+    DROP COLUMN IF EXISTS RUNTIME,
+    DROP COLUMN IF EXISTS ORIGINAL_TITLE;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- codes are validated by application against ISO 3166-1, ISO 639-1 and ISO 4217,
+-- certifications map country codes to ratings of that country, e.g. {"US": "PG-13"}
+ALTER TABLE MOVIES
+    ADD COLUMN IF NOT EXISTS ORIGINAL_TITLE TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS RUNTIME INT CHECK (RUNTIME > 0),
+    ADD COLUMN IF NOT EXISTS COUNTRIES TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS LANGUAGES TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS CERTIFICATIONS JSONB NOT NULL DEFAULT '{}' CHECK (JSONB_TYPEOF(CERTIFICATIONS) = 'object'),
+    ADD COLUMN IF NOT EXISTS BUDGET BIGINT CHECK (BUDGET >= 0),
+    ADD COLUMN IF NOT EXISTS BUDGET_CURRENCY CHAR(3),
+    ADD COLUMN IF NOT EXISTS BOX_OFFICE BIGINT CHECK (BOX_OFFICE >= 0),
+    ADD COLUMN IF NOT EXISTS BOX_OFFICE_CURRENCY CHAR(3),
+    ADD CONSTRAINT MOVIES_BUDGET_CURRENCY_CHECK CHECK ((BUDGET IS NULL) = (BUDGET_CURRENCY IS NULL)),
+    ADD CONSTRAINT MOVIES_BOX_OFFICE_CURRENCY_CHECK CHECK ((BOX_OFFICE IS NULL) = (BOX_OFFICE_CURRENCY IS NULL));
+
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_RUNTIME ON MOVIES (RUNTIME);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_COUNTRIES ON MOVIES USING GIN (COUNTRIES);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_LANGUAGES ON MOVIES USING GIN (LANGUAGES);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_CERTIFICATIONS ON MOVIES USING GIN (CERTIFICATIONS);
+CREATE INDEX IF NOT EXISTS IDX_MOVIES_ORIGINAL_TITLE_TRGM ON MOVIES USING GIN (ORIGINAL_TITLE GIN_TRGM_OPS);
\ No newline at end of file
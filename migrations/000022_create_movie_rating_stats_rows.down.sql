@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS TRIGGER_MOVIES_RATING_STATS ON MOVIES;
+DROP FUNCTION IF EXISTS CREATE_MOVIE_RATING_STATS();
\ No newline at end of file
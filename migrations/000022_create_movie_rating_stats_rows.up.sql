@@ -0,0 +1,23 @@
+-- every movie gets a stats row, so sorting by rating joins stats and is served by their average index
+INSERT INTO MOVIE_RATING_STATS (MOVIE_ID)
+SELECT
+    ID
+FROM
+    MOVIES
+ON CONFLICT (MOVIE_ID) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION CREATE_MOVIE_RATING_STATS()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO MOVIE_RATING_STATS (MOVIE_ID)
+    VALUES (NEW.ID)
+    ON CONFLICT (MOVIE_ID) DO NOTHING;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE PLPGSQL;
+
+CREATE TRIGGER TRIGGER_MOVIES_RATING_STATS
+AFTER INSERT ON MOVIES
+FOR EACH ROW
+EXECUTE FUNCTION CREATE_MOVIE_RATING_STATS();
\ No newline at end of file
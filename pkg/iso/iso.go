@@ -0,0 +1,139 @@
+// Package iso checks codes of countries (ISO 3166-1 alpha-2), languages (ISO 639-1)
+// and currencies (ISO 4217). Codes are case-sensitive and written the way standards do:
+// countries and currencies uppercase, languages lowercase
+package iso
+
+import "strings"
+
+// countryCodes are officially assigned ISO 3166-1 alpha-2 codes
+const countryCodes = `
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ
+	BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ
+	CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ
+	DE DJ DK DM DO DZ
+	EC EE EG EH ER ES ET
+	FI FJ FK FM FO FR
+	GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY
+	HK HM HN HR HT HU
+	ID IE IL IM IN IO IQ IR IS IT
+	JE JM JO JP
+	KE KG KH KI KM KN KP KR KW KY KZ
+	LA LB LC LI LK LR LS LT LU LV LY
+	MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ
+	NA NC NE NF NG NI NL NO NP NR NU NZ
+	OM
+	PA PE PF PG PH PK PL PM PN PR PS PT PW PY
+	QA
+	RE RO RS RU RW
+	SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ
+	TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ
+	UA UG UM US UY UZ
+	VA VC VE VG VI VN VU
+	WF WS
+	YE YT
+	ZA ZM ZW
+`
+
+// formerCountryCodes are ISO 3166-3 codes of countries which no longer exist,
+// old movies were produced there and still refer to them
+const formerCountryCodes = `CS DD SU YU`
+
+// languageCodes are ISO 639-1 codes, deprecated ones are left out
+const languageCodes = `
+	aa ab ae af ak am an ar as av ay az
+	ba be bg bi bm bn bo br bs
+	ca ce ch co cr cs cu cv cy
+	da de dv dz
+	ee el en eo es et eu
+	fa ff fi fj fo fr fy
+	ga gd gl gn gu gv
+	ha he hi ho hr ht hu hy hz
+	ia id ie ig ii ik io is it iu
+	ja jv
+	ka kg ki kj kk kl km kn ko kr ks ku kv kw ky
+	la lb lg li ln lo lt lu lv
+	mg mh mi mk ml mn mr ms mt my
+	na nb nd ne ng nl nn no nr nv ny
+	oc oj om or os
+	pa pi pl ps pt
+	qu
+	rm rn ro ru rw
+	sa sc sd se sg si sk sl sm sn so sq sr ss st su sv sw
+	ta te tg th ti tk tl tn to tr ts tt tw ty
+	ug uk ur uz
+	ve vi vo
+	wa wo
+	xh
+	yi yo
+	za zh zu
+`
+
+// currencyCodes are ISO 4217 codes of currencies in circulation,
+// fund codes, precious metals and testing codes are left out
+const currencyCodes = `
+	AED AFN ALL AMD AOA ARS AUD AWG AZN
+	BAM BBD BDT BGN BHD BIF BMD BND BOB BRL BSD BTN BWP BYN BZD
+	CAD CDF CHF CLP CNY COP CRC CUP CVE CZK
+	DJF DKK DOP DZD
+	EGP ERN ETB EUR
+	FJD FKP
+	GBP GEL GHS GIP GMD GNF GTQ GYD
+	HKD HNL HTG HUF
+	IDR ILS INR IQD IRR ISK
+	JMD JOD JPY
+	KES KGS KHR KMF KPW KRW KWD KYD KZT
+	LAK LBP LKR LRD LSL LYD
+	MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN
+	NAD NGN NIO NOK NPR NZD
+	OMR
+	PAB PEN PGK PHP PKR PLN PYG
+	QAR
+	RON RSD RUB RWF
+	SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL
+	THB TJS TMT TND TOP TRY TTD TWD TZS
+	UAH UGX USD UYU UZS
+	VED VES VND VUV
+	WST
+	XAF XCD XCG XOF XPF
+	YER
+	ZAR ZMW ZWG
+`
+
+// formerCurrencyCodes are ISO 4217 codes of withdrawn currencies which budgets and
+// box office of old movies were counted in, mostly predecessors of euro
+const formerCurrencyCodes = `
+	ANG ATS BEF BYR CUC CYP DEM EEK ESP FIM FRF GRD HRK IEP ITL LTL LUF LVL
+	MRO MTL NLG PTE SIT SKK SLL STD SUR VEF ZWL
+`
+
+var (
+	countries  = codeSet(countryCodes, formerCountryCodes)
+	languages  = codeSet(languageCodes)
+	currencies = codeSet(currencyCodes, formerCurrencyCodes)
+)
+
+func codeSet(lists ...string) map[string]bool {
+	set := map[string]bool{}
+	for _, list := range lists {
+		for _, code := range strings.Fields(list) {
+			set[code] = true
+		}
+	}
+
+	return set
+}
+
+// IsCountry reports whether code is ISO 3166-1 alpha-2 code or code of former country like SU
+func IsCountry(code string) bool {
+	return countries[code]
+}
+
+// IsLanguage reports whether code is ISO 639-1 code
+func IsLanguage(code string) bool {
+	return languages[code]
+}
+
+// IsCurrency reports whether code is ISO 4217 code of current or former currency
+func IsCurrency(code string) bool {
+	return currencies[code]
+}
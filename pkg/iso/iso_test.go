@@ -0,0 +1,62 @@
+package iso
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodeLists(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     string
+		size     int
+		expected int
+	}{
+		{"countries", countryCodes, 2, 249},
+		{"languages", languageCodes, 2, 183},
+		{"currencies", currencyCodes, 3, 156},
+	}
+
+	for _, tt := range tests {
+		codes := strings.Fields(tt.list)
+		if len(codes) != tt.expected {
+			t.Errorf("Expected %d %s, got %d", tt.expected, tt.name, len(codes))
+		}
+
+		if set := codeSet(tt.list); len(set) != len(codes) {
+			t.Errorf("Expected %s to be unique, %d of %d are", tt.name, len(set), len(codes))
+		}
+
+		for i, code := range codes {
+			if len(code) != tt.size || (i > 0 && code <= codes[i-1]) {
+				t.Errorf("Expected %s to be sorted codes of %d letters, got %q", tt.name, tt.size, code)
+			}
+		}
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	tests := []struct {
+		check    func(string) bool
+		code     string
+		expected bool
+	}{
+		{IsCountry, "US", true},
+		{IsCountry, "SU", true},
+		{IsCountry, "us", false},
+		{IsCountry, "UK", false},
+		{IsLanguage, "en", true},
+		{IsLanguage, "EN", false},
+		{IsLanguage, "eng", false},
+		{IsCurrency, "USD", true},
+		{IsCurrency, "FRF", true},
+		{IsCurrency, "XAU", false},
+		{IsCurrency, "usd", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.check(tt.code); got != tt.expected {
+			t.Errorf("Check of %q = %v, expected %v", tt.code, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Package jsonpatch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) documents
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPatch is returned when patch document is malformed
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrPathNotFound is returned when operation refers to a missing location of document
+	ErrPathNotFound = errors.New("path not found")
+	// ErrTestFailed is returned when value of "test" operation doesn't match the document
+	ErrTestFailed = errors.New("test operation failed")
+)
+
+// MergePatch applies RFC 7396 merge patch to doc, null members of patch remove fields
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %v", err)
+	}
+
+	var p any
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+		} else {
+			t[key] = merge(t[key], value)
+		}
+	}
+
+	return t
+}
+
+// Operation is a single step of RFC 6902 patch
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Apply applies RFC 6902 patch to doc, operations are applied in order and
+// the whole patch fails when any of them fails
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %v", err)
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	for i, op := range ops {
+		var err error
+		if target, err = apply(target, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func apply(doc any, op Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("%w: value is required", ErrInvalidPatch)
+		}
+
+		var value any
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		default:
+			return doc, test(doc, path, value)
+		}
+
+	case "remove":
+		return remove(doc, path)
+
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "copy" {
+			return add(doc, path, clone(value))
+		}
+
+		if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+			return nil, fmt.Errorf("%w: can't move value into its own child", ErrInvalidPatch)
+		}
+
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, value)
+	}
+
+	return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, op.Op)
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return modify(doc, path, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[key] = value
+			return c, nil
+		case []any:
+			if key == "-" {
+				return append(c, value), nil
+			}
+
+			i, err := arrayIndex(key, len(c)+1)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(c[:i], append([]any{value}, c[i:]...)...), nil
+		}
+
+		return nil, fmt.Errorf("%w: parent is not a container", ErrPathNotFound)
+	})
+}
+
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: can't remove the whole document", ErrInvalidPatch)
+	}
+
+	return modify(doc, path, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[key]; !ok {
+				return nil, ErrPathNotFound
+			}
+
+			delete(c, key)
+			return c, nil
+		case []any:
+			i, err := arrayIndex(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+
+			return append(c[:i], c[i+1:]...), nil
+		}
+
+		return nil, ErrPathNotFound
+	})
+}
+
+func replace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return modify(doc, path, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[key]; !ok {
+				return nil, ErrPathNotFound
+			}
+
+			c[key] = value
+			return c, nil
+		case []any:
+			i, err := arrayIndex(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+
+			c[i] = value
+			return c, nil
+		}
+
+		return nil, ErrPathNotFound
+	})
+}
+
+func test(doc any, path []string, value any) error {
+	actual, err := get(doc, path)
+	if err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(actual, value) {
+		return ErrTestFailed
+	}
+
+	return nil
+}
+
+// modify walks down to the parent of the last token and replaces it with result of fn,
+// containers are returned back up because appending to slices may reallocate them
+func modify(node any, path []string, fn func(container any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+
+	child, err := get(node, path[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	child, err = modify(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := node.(type) {
+	case map[string]any:
+		c[path[0]] = child
+	case []any:
+		i, _ := arrayIndex(path[0], len(c))
+		c[i] = child
+	}
+
+	return node, nil
+}
+
+func get(node any, path []string) (any, error) {
+	for _, key := range path {
+		switch c := node.(type) {
+		case map[string]any:
+			value, ok := c[key]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			node = value
+		case []any:
+			i, err := arrayIndex(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+			node = c[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+
+	return node, nil
+}
+
+// parsePointer splits RFC 6901 JSON pointer into unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: pointer %q must start with /", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses array index which must be less than size
+func arrayIndex(token string, size int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrPathNotFound, token)
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i >= size {
+		return 0, fmt.Errorf("%w: array index %s is out of range", ErrPathNotFound, token)
+	}
+
+	return i, nil
+}
+
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for key, item := range v {
+			c[key] = clone(item)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, item := range v {
+			c[i] = clone(item)
+		}
+		return c
+	}
+
+	return value
+}